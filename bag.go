@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/battle"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"io"
+	"sort"
+)
+
+func bag(ctx context.Context, c *config, s ...string) (any, error) {
+	res := bagResult{Items: []bagEntry{}}
+	describe := true
+	for _, item := range pokemon.Pokemons.Items() {
+		entry := bagEntry{Name: item.Name, Count: item.Count}
+		// descriptions are nice to have, the bag still works without them
+		if describe {
+			data, err := c.client.Item(ctx, item.Name)
+			switch {
+			case ctx.Err() != nil:
+				return nil, ctx.Err()
+			case err == nil:
+				entry.Category = data.Category.Name
+				entry.Effect = data.ShortEffect()
+			case !errors.Is(err, pokeapi.ErrNotFound):
+				// the rest would only wait on the same broken network
+				describe = false
+			}
+		}
+		res.Items = append(res.Items, entry)
+	}
+	return res, nil
+}
+
+// bagItems is every item the bag can get, besides the ones pokemon evolve
+// with, for snapshots to have their descriptions
+func bagItems() []string {
+	seen := map[string]bool{"rare-candy": true}
+	for name := range pokemon.StarterBag {
+		seen[name] = true
+	}
+	for name := range pokemon.Balls {
+		seen[name] = true
+	}
+	for name := range pokemon.Heals {
+		seen[name] = true
+	}
+	items := make([]string, 0, len(seen))
+	for name := range seen {
+		items = append(items, name)
+	}
+	sort.Strings(items)
+	return items
+}
+
+func useItem(ctx context.Context, c *config, args ...string) (any, error) {
+	if len(args) < 1 {
+		return nil, usageOf("use")
+	}
+	item := args[0]
+	if pokemon.Pokemons.Count(item) == 0 {
+		return nil, fmt.Errorf("You don't have any %s in your bag", item)
+	}
+	if _, ok := pokemon.Balls[item]; ok {
+		return nil, usageError("catch <pokemon-name> --ball " + item)
+	}
+	if len(args) < 2 {
+		return nil, usageOf("use")
+	}
+	caught, err := pokemon.Pokemons.Find(args[1])
+	if err != nil {
+		return nil, err
+	}
+	res := useResult{Item: item, Pokemon: caught.Name()}
+
+	if heal, ok := pokemon.Heals[item]; ok {
+		p, err := pokemon.Pokemons.Get(caught.Species)
+		if err != nil {
+			return nil, err
+		}
+		maxHP := maxHP(p, caught.Level)
+		damage, err := heal.Apply(caught, maxHP)
+		if err != nil {
+			return nil, err
+		}
+		err = pokemon.Pokemons.UseItem(item)
+		if err != nil {
+			return nil, err
+		}
+		err = pokemon.Pokemons.SetDamage(caught.ID, damage)
+		if err != nil {
+			return nil, err
+		}
+		res.HP, res.MaxHP = maxHP-damage, maxHP
+		return res, nil
+	}
+
+	if item == "rare-candy" {
+		if caught.Level >= pokemon.MaxLevel {
+			return nil, fmt.Errorf("%s is already level %d", caught.Name(), pokemon.MaxLevel)
+		}
+		err = pokemon.Pokemons.UseItem(item)
+		if err != nil {
+			return nil, err
+		}
+		res.Level = pokemon.Pokemons.LevelUp(caught.ID)
+		return res, nil
+	}
+
+	// anything else might be an evolution item
+	species, err := pokemon.Pokemons.NextEvolution(ctx, c.client, caught, "", item)
+	if err != nil {
+		return nil, err
+	}
+	err = pokemon.Pokemons.UseItem(item)
+	if err != nil {
+		return nil, err
+	}
+	evolved, err := evolveInto(ctx, c, caught, species)
+	if err != nil {
+		// the item wasn't used up after all
+		pokemon.Pokemons.AddItem(item, 1)
+		return nil, err
+	}
+	res.EvolvedInto = evolved.To
+	return res, nil
+}
+
+func maxHP(p pokemon.PokemonEndpoint, level int) int {
+	for _, stat := range p.Stats {
+		if stat.Stat.Name == "hp" {
+			return battle.MaxHP(stat.BaseStat, level)
+		}
+	}
+	return battle.MaxHP(0, level)
+}
+
+type bagEntry struct {
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	Category string `json:"category,omitempty"`
+	Effect   string `json:"effect,omitempty"`
+}
+
+type bagResult struct {
+	Items []bagEntry `json:"items"`
+}
+
+func (r bagResult) Text(w io.Writer) error {
+	if len(r.Items) == 0 {
+		_, err := fmt.Fprintln(w, "Your bag is empty")
+		return err
+	}
+	fmt.Fprintln(w, "==Your Bag==")
+	for _, item := range r.Items {
+		if item.Effect == "" {
+			fmt.Fprintf(w, "%s x%d\n", item.Name, item.Count)
+			continue
+		}
+		fmt.Fprintf(w, "%s x%d: %s\n", item.Name, item.Count, item.Effect)
+	}
+	return nil
+}
+
+type useResult struct {
+	Item    string `json:"item"`
+	Pokemon string `json:"pokemon"`
+	// set depending on what the item did
+	HP          int    `json:"hp,omitempty"`
+	MaxHP       int    `json:"max_hp,omitempty"`
+	Level       int    `json:"level,omitempty"`
+	EvolvedInto string `json:"evolved_into,omitempty"`
+}
+
+func (r useResult) Text(w io.Writer) error {
+	switch {
+	case r.EvolvedInto != "":
+		_, err := fmt.Fprintf(w, "Used %s on %s, it evolved into %s!\n", r.Item, r.Pokemon, r.EvolvedInto)
+		return err
+	case r.Level != 0:
+		_, err := fmt.Fprintf(w, "Used %s on %s, it grew to level %d!\n", r.Item, r.Pokemon, r.Level)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "Used %s on %s, it has %d/%d HP\n", r.Item, r.Pokemon, r.HP, r.MaxHP)
+	return err
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/battle"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// how many moves a pokemon takes into battle, and how many of its
+	// learnset we look up to find them
+	battleMoves    = 4
+	maxMoveLookups = 12
+)
+
+func battlePokemon(ctx context.Context, c *config, args ...string) (any, error) {
+	if len(args) < 2 {
+		return nil, usageOf("battle")
+	}
+	seed := time.Now().UnixNano()
+	if len(args) > 2 && args[2] != "" {
+		n, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return nil, usageOf("battle")
+		}
+		seed = n
+	}
+
+	caught, err := pokemon.Pokemons.Find(args[0])
+	if err != nil {
+		return nil, err
+	}
+	mine, err := pokemon.Pokemons.Get(caught.Species)
+	if err != nil {
+		return nil, err
+	}
+	wild, err := pokemon.Info(ctx, c.client, args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	// the wild pokemon is always at the same level as yours
+	level := caught.Level
+	a, err := newBattler(ctx, c.client, c.game, mine, level)
+	if err != nil {
+		return nil, err
+	}
+	a.Name = caught.Name()
+	// damage stays with a pokemon between battles until it's healed
+	a.HP = max(a.Stats.HP-caught.Damage, 0)
+	if a.Fainted() {
+		return nil, fmt.Errorf("%s has fainted, use a revive on it first", a.Name)
+	}
+	b, err := newBattler(ctx, c.client, c.game, wild, level)
+	if err != nil {
+		return nil, err
+	}
+	// the wild pokemon gets a name of its own in case it's the same species
+	if b.Name == a.Name {
+		b.Name = "wild " + b.Name
+	}
+
+	var chart battle.Chart = c.types
+	err = c.types.Load(ctx, append(a.Types, b.Types...)...)
+	if errors.Is(err, context.Canceled) {
+		return nil, err
+	}
+	if err != nil {
+		// without PokeAPI's types the battle goes on with the chart of today's games
+		chart = battle.DefaultChart
+	}
+	result := battle.Fight(a, b, chart, rand.New(rand.NewSource(seed)))
+	res := battleResult{
+		Seed:   seed,
+		Mine:   a.Name,
+		Wild:   b.Name,
+		Level:  level,
+		Result: result,
+	}
+	err = pokemon.Pokemons.SetDamage(caught.ID, a.Stats.HP-a.HP)
+	if err != nil {
+		return nil, err
+	}
+	if result.Winner == a.Name && level < pokemon.MaxLevel {
+		res.Level = pokemon.Pokemons.LevelUp(caught.ID)
+		res.LeveledUp = true
+	}
+	return res, nil
+}
+
+// a pokemon with the types and moves it had in game
+func newBattler(ctx context.Context, client *pokeapi.Client, game pokemon.Game, p pokemon.PokemonEndpoint, level int) (*battle.Battler, error) {
+	var base battle.Stats
+	for _, stat := range p.Stats {
+		switch stat.Stat.Name {
+		case "hp":
+			base.HP = stat.BaseStat
+		case "attack":
+			base.Attack = stat.BaseStat
+		case "defense":
+			base.Defense = stat.BaseStat
+		case "special-attack":
+			base.SpecialAttack = stat.BaseStat
+		case "special-defense":
+			base.SpecialDefense = stat.BaseStat
+		case "speed":
+			base.Speed = stat.BaseStat
+		}
+	}
+	moves, err := battleMoveset(ctx, client, game, p, level)
+	if err != nil {
+		return nil, err
+	}
+	return battle.NewBattler(p.Name, level, game.Types(p), base, moves), nil
+}
+
+// the most recently learned damaging level-up moves, like a wild pokemon in the games
+func battleMoveset(ctx context.Context, client *pokeapi.Client, game pokemon.Game, p pokemon.PokemonEndpoint, level int) ([]battle.Move, error) {
+	type learned struct {
+		name  string
+		level int
+	}
+	var candidates []learned
+	for _, move := range p.Moves {
+		learnedAt := -1
+		for _, detail := range move.VersionGroupDetails {
+			if !game.HasVersionGroup(detail.VersionGroup.Name) {
+				continue
+			}
+			if detail.MoveLearnMethod.Name == "level-up" && detail.LevelLearnedAt <= level && detail.LevelLearnedAt > learnedAt {
+				learnedAt = detail.LevelLearnedAt
+			}
+		}
+		if learnedAt >= 0 {
+			candidates = append(candidates, learned{move.Move.Name, learnedAt})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].level > candidates[j].level
+	})
+
+	var moves []battle.Move
+	for i, candidate := range candidates {
+		if len(moves) == battleMoves || i == maxMoveLookups {
+			break
+		}
+		move, err := client.Move(ctx, candidate.name)
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if move.Power == 0 || move.DamageClass.Name == "status" {
+			continue
+		}
+		moves = append(moves, battle.Move{
+			Name:        move.Name,
+			Type:        move.Type.Name,
+			DamageClass: move.DamageClass.Name,
+			Power:       move.Power,
+			Accuracy:    move.Accuracy,
+			Priority:    move.Priority,
+		})
+	}
+	return moves, nil
+}
+
+type battleResult struct {
+	// replaying with the same seed gives the same battle
+	Seed int64  `json:"seed"`
+	Mine string `json:"mine"`
+	Wild string `json:"wild"`
+	// the level of your pokemon after the battle
+	Level     int  `json:"level"`
+	LeveledUp bool `json:"leveled_up"`
+	battle.Result
+}
+
+func (r battleResult) Text(w io.Writer) error {
+	fmt.Fprintf(w, "%s vs %s (seed %d)\n", r.Mine, r.Wild, r.Seed)
+	turn := 0
+	for _, event := range r.Events {
+		if event.Turn != turn {
+			turn = event.Turn
+			fmt.Fprintf(w, "==TURN %d==\n", turn)
+		}
+		fmt.Fprintf(w, "%s used %s!\n", event.Attacker, event.Move)
+		if event.Missed {
+			fmt.Fprintln(w, "It missed!")
+			continue
+		}
+		if event.Critical {
+			fmt.Fprintln(w, "A critical hit!")
+		}
+		switch {
+		case event.Effectiveness == 0:
+			fmt.Fprintf(w, "It doesn't affect %s...\n", event.Defender)
+		case event.Effectiveness > 1:
+			fmt.Fprintln(w, "It's super effective!")
+		case event.Effectiveness < 1:
+			fmt.Fprintln(w, "It's not very effective...")
+		}
+		fmt.Fprintf(w, "%s took %d damage (%d HP left)\n", event.Defender, event.Damage, event.DefenderHP)
+		if event.Fainted {
+			fmt.Fprintf(w, "%s fainted!\n", event.Defender)
+		}
+	}
+	if r.Winner == "" {
+		_, err := fmt.Fprintf(w, "Neither side gave in after %d turns, it's a draw\n", r.Turns)
+		return err
+	}
+	fmt.Fprintf(w, "%s won the battle!\n", r.Winner)
+	if r.LeveledUp {
+		fmt.Fprintf(w, "%s grew to level %d!\n", r.Mine, r.Level)
+	}
+	return nil
+}
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/fuzzy"
+	"github.com/srijan-raghavula/pokedex/internal/render"
+	"os"
+	"sort"
+	"strings"
+)
+
+// command is everything the dispatcher needs to run a command and explain
+// it in help. the callback gets the positional arguments, then the value of
+// each flag in the order they're declared, "" for flags that weren't given
+type command struct {
+	name        string
+	aliases     []string
+	description string
+	args        []argument
+	flags       []flagSpec
+	// only for commands whose usage the arguments can't describe
+	usageText string
+	// ctx is cancelled when the user presses Ctrl-C
+	callback func(context.Context, *config, ...string) (any, error)
+}
+
+type argument struct {
+	name     string
+	optional bool
+	// takes every argument left, only the last argument can
+	variadic bool
+	// left as typed instead of lowercased, like file paths
+	keepCase bool
+}
+
+type flagSpec struct {
+	name string
+	// what the value looks like in usage, empty for flags without a value
+	value       string
+	description string
+	keepCase    bool
+}
+
+// flags every command takes
+var globalFlags = []flagSpec{
+	{name: "output", value: "text|json|table", description: "how the result is printed"},
+	{name: "json", description: "shorthand for --output json"},
+}
+
+var commands map[string]command
+
+func newCommands() map[string]command {
+	list := []command{
+		{
+			name:        "help",
+			description: "prints a message to help yourself using Pokedex, or everything about one command",
+			args:        []argument{{name: "command", optional: true}},
+			callback:    printCommands,
+		},
+		{
+			name:        "exit",
+			aliases:     []string{"quit"},
+			description: "exits Pokedex",
+			callback:    exit,
+		},
+		{
+			name:        "map",
+			description: "prints the next 20 location areas in the pokemon world, or the first, the last or any page of them",
+			args:        []argument{{name: "first|last|goto", optional: true}, {name: "page", optional: true}},
+			callback:    mapNext,
+		},
+		{
+			name:        "mapb",
+			description: "goes back to the location areas map printed before (returns an error if there's nowhere to go back to)",
+			callback:    mapPrev,
+		},
+		{
+			name:        "list",
+			description: "lists pokemon, moves, items, berries, types or locations a page at a time, each keeping its own page to go to the next, prev, first or last one",
+			args:        []argument{{name: "resource"}, {name: "next|prev|first|last", optional: true}},
+			flags: []flagSpec{
+				{name: "page", value: "N", description: "goes to page N"},
+				{name: "limit", value: "M", description: "shows M results a page, 20 by default"},
+			},
+			callback: list,
+		},
+		{
+			name:        "search",
+			description: "finds the pokemon, location areas, moves and items whose names start with or are spelled like a word",
+			args:        []argument{{name: "term"}},
+			flags: []flagSpec{
+				{name: "kind", value: "pokemon|location-area|move|item", description: "only finds names of one kind"},
+			},
+			callback: searchNames,
+		},
+		{
+			name:        "explore",
+			description: "takes a location area and lists all the Pokemons in the area",
+			args:        []argument{{name: "location-area-name"}},
+			callback:    pokemonList,
+		},
+		{
+			name:        "goto",
+			description: "takes a location area and goes there, catch only finds the Pokemons of the area you are in",
+			args:        []argument{{name: "location-area-name"}},
+			callback:    goTo,
+		},
+		{
+			name:        "walk",
+			aliases:     []string{"encounter"},
+			description: "looks for a wild Pokemon in the area you are in, weighted by how common each one is",
+			flags: []flagSpec{
+				{name: "method", value: "walk|surf|old-rod|...", description: "how to look, walking through grass by default"},
+				{name: "version", value: "NAME", description: "the game version whose encounter table is used"},
+				{name: "seed", value: "N", description: "finds the same Pokemon as an earlier walk with this seed"},
+			},
+			callback: walk,
+		},
+		{
+			name:        "catch",
+			description: "catches a pokemon from the area you are in and adds it to Pokedex, and to your party if there's room",
+			args:        []argument{{name: "pokemon-name"}},
+			flags: []flagSpec{
+				{name: "nickname", value: "NAME", description: "names the Pokemon", keepCase: true},
+				{name: "ball", value: "BALL", description: "the ball from your bag to throw, a poke-ball by default"},
+				{name: "status", value: "STATUS", description: "the Pokemon's status, sleeping Pokemons are easier to catch"},
+			},
+			callback: catchPokemon,
+		},
+		{
+			name:        "inspect",
+			description: "inspects a Pokemon in your Pokedex and shows the details of the Pokemon",
+			args:        []argument{{name: "pokemon-name"}},
+			flags: []flagSpec{
+				{name: "sprite", value: "front|shiny|back|gen1", description: "draws one of the Pokemon's sprites"},
+			},
+			callback: inspectPokemon,
+		},
+		{
+			name:        "pokedex",
+			description: "lists all the Pokemons caught",
+			callback:    pokedex,
+		},
+		{
+			name:        "battle",
+			description: "takes one of your Pokemons and a wild Pokemon and makes them battle",
+			args:        []argument{{name: "your-pokemon"}, {name: "wild-pokemon"}},
+			flags: []flagSpec{
+				{name: "seed", value: "N", description: "replays an earlier battle with this seed"},
+			},
+			callback: battlePokemon,
+		},
+		{
+			name:        "matchup",
+			description: "takes a Pokemon and lists the types it is weak to, resists and is immune to",
+			args:        []argument{{name: "pokemon-name"}},
+			callback:    matchup,
+		},
+		{
+			name:        "counter",
+			description: "takes a Pokemon and ranks the Pokemons in your Pokedex by how well their types fare against it",
+			args:        []argument{{name: "pokemon-name"}},
+			callback:    counter,
+		},
+		{
+			name:        "evolutions",
+			description: "takes a Pokemon and shows its evolution chain with what each evolution needs",
+			args:        []argument{{name: "pokemon-name"}},
+			callback:    evolutions,
+		},
+		{
+			name:        "evolve",
+			description: "evolves one of your Pokemons once it meets the conditions (optionally takes the Pokemon to evolve into)",
+			args:        []argument{{name: "pokemon-name"}, {name: "into", optional: true}},
+			callback:    evolve,
+		},
+		{
+			name:        "party",
+			description: "manages the six Pokemons you take into battle: party list, party add <pokemon>, party remove <pokemon>, party swap <slot> <slot>",
+			args:        []argument{{name: "action"}, {name: "pokemon", variadic: true}},
+			usageText:   partyUsageText,
+			callback:    party,
+		},
+		{
+			name:        "bag",
+			description: "lists the balls, potions, berries and other items in your bag",
+			callback:    bag,
+		},
+		{
+			name:        "use",
+			description: "uses an item from your bag on one of your Pokemons, like a potion, rare-candy or thunder-stone",
+			args:        []argument{{name: "item"}, {name: "pokemon"}},
+			callback:    useItem,
+		},
+		{
+			name:        "version",
+			description: "picks the game whose encounters, learnsets, sprites and types are used, like platinum (version all goes back to every game, no name shows the current one)",
+			args:        []argument{{name: "version-name", optional: true}},
+			callback:    version,
+		},
+		{
+			name:        "save",
+			description: "saves your Pokedex to disk (it is also saved automatically on exit)",
+			callback:    save,
+		},
+		{
+			name:        "snapshot",
+			description: "downloads location areas and their Pokemons into a local archive for --offline mode (optionally takes the max number of areas to download)",
+			args:        []argument{{name: "max-areas", optional: true}},
+			callback:    takeSnapshot,
+		},
+		{
+			name:        "load",
+			description: "takes a save file and loads the Pokedex from it, replacing the current one",
+			args:        []argument{{name: "save-file", keepCase: true}},
+			callback:    load,
+		},
+	}
+	registry := make(map[string]command, len(list))
+	for _, cmd := range list {
+		registry[cmd.name] = cmd
+	}
+	return registry
+}
+
+// findCommand looks a command up by its name or one of its aliases
+func findCommand(name string) (command, bool) {
+	if cmd, ok := commands[name]; ok {
+		return cmd, true
+	}
+	for _, cmd := range commands {
+		for _, alias := range cmd.aliases {
+			if alias == name {
+				return cmd, true
+			}
+		}
+	}
+	return command{}, false
+}
+
+// usage is the command's usage line, built from its arguments and flags
+func (cmd command) usage() string {
+	if cmd.usageText != "" {
+		return cmd.usageText
+	}
+	parts := []string{cmd.name}
+	for _, arg := range cmd.args {
+		part := "<" + arg.name + ">"
+		if arg.optional {
+			part = "[" + arg.name + "]"
+		}
+		if arg.variadic {
+			part += "..."
+		}
+		parts = append(parts, part)
+	}
+	for _, flag := range cmd.flags {
+		parts = append(parts, "["+flag.usage()+"]")
+	}
+	return strings.Join(parts, " ")
+}
+
+func (f flagSpec) usage() string {
+	if f.value == "" {
+		return "--" + f.name
+	}
+	return "--" + f.name + " " + f.value
+}
+
+func (cmd command) flag(name string) (flagSpec, bool) {
+	for _, flag := range append(globalFlags, cmd.flags...) {
+		if flag.name == name {
+			return flag, true
+		}
+	}
+	return flagSpec{}, false
+}
+
+// flags that take a value, so "--output json" works as well as "--output=json"
+func valueFlags() map[string]bool {
+	names := make(map[string]bool)
+	flags := globalFlags
+	for _, cmd := range commands {
+		flags = append(flags, cmd.flags...)
+	}
+	for _, flag := range flags {
+		if flag.value != "" {
+			names[flag.name] = true
+		}
+	}
+	return names
+}
+
+// usageOf is the usage error of a command, for callbacks that find out
+// late that they were called wrong
+func usageOf(name string) usageError {
+	return usageError(commands[name].usage())
+}
+
+// runs a single line of input as a command
+func runCommand(ctx context.Context, c *config, stdIn string) error {
+	return runWords(ctx, c, strings.Fields(stdIn))
+}
+
+// runs a command already split into words, like the arguments pokedex was
+// started with, where a word can have spaces in it
+func runWords(ctx context.Context, c *config, argv []string) error {
+	words, flags := splitFlags(argv)
+	if len(words) == 0 {
+		// an empty line does nothing, like in a shell
+		if len(flags) > 0 {
+			return usageError("<command> [arguments] [--flags]")
+		}
+		return nil
+	}
+	name := strings.ToLower(words[0])
+	cmd, ok := findCommand(name)
+	if !ok {
+		return unknownCommandError(name)
+	}
+	args, err := cmd.parse(words[1:], flags)
+	if err != nil {
+		return err
+	}
+
+	output, err := outputFormat(c, flags)
+	if err != nil {
+		return err
+	}
+	c.output = output
+	defer func() { c.output = c.outputDefault }()
+
+	res, err := call(ctx, c, cmd, args)
+	if err != nil {
+		return err
+	}
+	return render.Render(os.Stdout, c.output, res)
+}
+
+// parse checks the arguments and flags against what the command declares
+// and puts them in the order its callback takes them
+func (cmd command) parse(words []string, flags map[string]string) ([]string, error) {
+	for name := range flags {
+		if _, ok := cmd.flag(name); !ok {
+			return nil, usageError(fmt.Sprintf("%s doesn't take --%s (%s)", cmd.name, name, cmd.usage()))
+		}
+	}
+
+	required, variadic := 0, false
+	for _, arg := range cmd.args {
+		if !arg.optional && !arg.variadic {
+			required++
+		}
+		variadic = variadic || arg.variadic
+	}
+	if len(words) < required || (!variadic && len(words) > len(cmd.args)) {
+		return nil, usageError(cmd.usage())
+	}
+
+	args := make([]string, 0, len(words)+len(cmd.flags))
+	for i, word := range words {
+		arg := cmd.args[min(i, len(cmd.args)-1)]
+		if !arg.keepCase {
+			word = strings.ToLower(word)
+		}
+		args = append(args, word)
+	}
+	if len(cmd.flags) == 0 {
+		return args, nil
+	}
+	// flags come after every argument, given or not
+	for len(args) < len(cmd.args) {
+		args = append(args, "")
+	}
+	for _, flag := range cmd.flags {
+		value := flags[flag.name]
+		if !flag.keepCase {
+			value = strings.ToLower(value)
+		}
+		args = append(args, value)
+	}
+	return args, nil
+}
+
+// printCommands lists every command, or explains one of them
+func printCommands(ctx context.Context, c *config, s ...string) (any, error) {
+	if len(commands) == 0 {
+		return nil, errors.New("no commands yet")
+	}
+	if len(s) > 0 && s[0] != "" {
+		cmd, ok := findCommand(s[0])
+		if !ok {
+			return nil, unknownCommandError(s[0])
+		}
+		return newCommandHelp(cmd), nil
+	}
+	res := helpResult{}
+	for _, cmd := range commands {
+		if cmd.description == "" {
+			return nil, errors.New("command not described")
+		}
+		res.Commands = append(res.Commands, newCommandHelp(cmd))
+	}
+	sort.Slice(res.Commands, func(i, j int) bool {
+		return res.Commands[i].Name < res.Commands[j].Name
+	})
+	return res, nil
+}
+
+func newCommandHelp(cmd command) commandHelp {
+	help := commandHelp{
+		Name:        cmd.name,
+		Aliases:     cmd.aliases,
+		Usage:       cmd.usage(),
+		Description: cmd.description,
+	}
+	for _, flag := range cmd.flags {
+		help.Flags = append(help.Flags, flagHelp{Flag: flag.usage(), Description: flag.description})
+	}
+	return help
+}
+
+type unknownCommandError string
+
+func (e unknownCommandError) Error() string {
+	names := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+		names = append(names, cmd.aliases...)
+	}
+	suggestions := fuzzy.Suggest(string(e), names, fuzzy.Threshold(string(e)))
+	if len(suggestions) == 0 {
+		return fmt.Sprintf("unknown command: %s (use \"help\" to see every command)", string(e))
+	}
+	return fmt.Sprintf("unknown command: %s, did you mean %s?", string(e), strings.Join(suggestions, " or "))
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"github.com/srijan-raghavula/pokedex/internal/lineedit"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// the REPL's history lives next to the save
+func historyPath(savePath string) string {
+	return filepath.Join(filepath.Dir(savePath), "history")
+}
+
+// completer completes command names, then the arguments the command takes:
+// location areas from the last map page, caught pokemon and bag items
+func completer(c *config) lineedit.CompleteFunc {
+	return func(line string) []string {
+		words := strings.Fields(line)
+		// after a space a new word is being typed
+		if len(words) == 0 || strings.HasSuffix(line, " ") {
+			words = append(words, "")
+		}
+		if len(words) == 1 {
+			return commandNames()
+		}
+		arg := len(words) - 1
+		switch strings.ToLower(words[0]) {
+		case "help":
+			if arg == 1 {
+				return commandNames()
+			}
+		case "map":
+			if arg == 1 {
+				return []string{"first", "last", "goto"}
+			}
+		case "list":
+			if arg == 1 {
+				return listResourceNames()
+			}
+			if arg == 2 {
+				return []string{"next", "prev", "first", "last"}
+			}
+		case "explore", "goto":
+			if arg == 1 {
+				return c.areas
+			}
+		case "inspect", "matchup", "counter", "evolutions":
+			if arg == 1 {
+				return pokemon.Pokemons.Names()
+			}
+		case "evolve", "battle":
+			if arg == 1 {
+				return caughtNames()
+			}
+		case "party":
+			if arg == 1 {
+				return []string{"list", "add", "remove", "swap"}
+			}
+			if arg == 2 && (words[1] == "add" || words[1] == "remove") {
+				return caughtNames()
+			}
+		case "use":
+			if arg == 1 {
+				var items []string
+				for _, item := range pokemon.Pokemons.Items() {
+					items = append(items, item.Name)
+				}
+				return items
+			}
+			if arg == 2 {
+				return caughtNames()
+			}
+		}
+		return nil
+	}
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name, cmd := range commands {
+		names = append(names, name)
+		names = append(names, cmd.aliases...)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// the species and nicknames commands taking one of your pokemon accept
+func caughtNames() []string {
+	names := pokemon.Pokemons.Names()
+	for _, caught := range pokemon.Pokemons.Instances() {
+		if caught.Nickname != "" {
+			names = append(names, caught.Nickname)
+		}
+	}
+	return names
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"github.com/srijan-raghavula/pokedex/internal/render"
+	"io"
+	"net"
+	"net/url"
+)
+
+// errorKind sorts the errors a command fails with, so every kind is
+// printed and turned into an exit code the same way wherever it happens
+type errorKind string
+
+const (
+	// the command was typed wrong
+	kindUsage errorKind = "usage"
+	// the command can't do what was asked, like catching a pokemon that
+	// isn't around
+	kindUser errorKind = "user"
+	// PokeAPI couldn't be reached or failed to answer
+	kindNetwork errorKind = "network"
+	// a bug in Pokedex
+	kindInternal errorKind = "internal"
+	// the user pressed Ctrl-C
+	kindInterrupted errorKind = "interrupted"
+)
+
+type usageError string
+
+func (e usageError) Error() string {
+	return "usage: " + string(e)
+}
+
+// internalError is a command that panicked, the session goes on without it
+type internalError struct {
+	value any
+}
+
+func (e internalError) Error() string {
+	return fmt.Sprintf("internal error: %v", e.value)
+}
+
+func kindOf(err error) errorKind {
+	var usageErr usageError
+	var unknownErr unknownCommandError
+	var internalErr internalError
+	switch {
+	case errors.As(err, &usageErr) || errors.As(err, &unknownErr):
+		return kindUsage
+	case errors.As(err, &internalErr):
+		return kindInternal
+	// before network errors, a cancelled request is wrapped in a url.Error
+	case errors.Is(err, context.Canceled):
+		return kindInterrupted
+	case isNetworkError(err):
+		return kindNetwork
+	}
+	return kindUser
+}
+
+// a 404 is the user asking for something that doesn't exist, or that an
+// offline snapshot doesn't have. any other status or a request that never
+// got an answer is the network's fault
+func isNetworkError(err error) bool {
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return false
+	}
+	var statusErr *pokeapi.StatusError
+	if errors.As(err, &statusErr) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	// a url.Error is a net.Error whatever went wrong, like a snapshot that
+	// can't be read, only what it wraps says if the network failed
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// runs a command's callback, turning a panic into an internalError
+func call(ctx context.Context, c *config, cmd command, args []string) (res any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			res, err = nil, internalError{value: r}
+		}
+	}()
+	res, err = cmd.callback(ctx, c, args...)
+	return res, withSuggestions(ctx, c, err)
+}
+
+type errorResult struct {
+	Kind    errorKind `json:"kind"`
+	Message string    `json:"message"`
+	// where a script failed, like "script.txt:4"
+	Where string `json:"where,omitempty"`
+	// there's no connection to check when lookups come from a snapshot
+	offline bool
+}
+
+func newErrorResult(err error) errorResult {
+	return errorResult{Kind: kindOf(err), Message: err.Error()}
+}
+
+func (r errorResult) Text(w io.Writer) error {
+	message := r.Message
+	switch r.Kind {
+	case kindNetwork:
+		if r.offline {
+			message = "network error: " + message
+			break
+		}
+		message = fmt.Sprintf("network error: %s (check your connection, or use --offline with a snapshot)", message)
+	case kindInternal:
+		message += " (this is a bug, the command was stopped)"
+	case kindInterrupted:
+		message = "interrupted (press Ctrl-C again to save and exit)"
+	}
+	if r.Where != "" {
+		message = r.Where + ": " + message
+	}
+	_, err := fmt.Fprintln(w, message)
+	return err
+}
+
+// printError prints err in the session's output format
+func printError(w io.Writer, c *config, err error, where string) {
+	res := newErrorResult(err)
+	res.Where = where
+	res.offline = c.offline
+	if render.Render(w, c.outputDefault, res) != nil {
+		fmt.Fprintln(w, err)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"io"
+	"strings"
+)
+
+func evolutions(ctx context.Context, c *config, name ...string) (any, error) {
+	p, err := lookupPokemon(ctx, c, name[0])
+	if err != nil {
+		return nil, err
+	}
+	chain, err := pokemon.EvolutionChain(ctx, c.client, p.Species.Name)
+	if err != nil {
+		return nil, err
+	}
+	return evolutionsResult{Pokemon: p.Name, Chain: newEvolutionNode(chain)}, nil
+}
+
+func evolve(ctx context.Context, c *config, args ...string) (any, error) {
+	into := ""
+	if len(args) > 1 {
+		into = args[1]
+	}
+	caught, err := pokemon.Pokemons.Find(args[0])
+	if err != nil {
+		return nil, err
+	}
+	species, err := pokemon.Pokemons.NextEvolution(ctx, c.client, caught, into, "")
+	if err != nil {
+		return nil, err
+	}
+	return evolveInto(ctx, c, caught, species)
+}
+
+// species is the species name from the evolution chain
+func evolveInto(ctx context.Context, c *config, caught pokemon.Caught, species string) (evolveResult, error) {
+	s, err := c.client.Species(ctx, species)
+	if err != nil {
+		return evolveResult{}, err
+	}
+	evolved, err := pokemon.Info(ctx, c.client, s.DefaultPokemon())
+	if err != nil {
+		return evolveResult{}, err
+	}
+	evolvedCaught, err := pokemon.Pokemons.Evolve(caught.ID, evolved)
+	if err != nil {
+		return evolveResult{}, err
+	}
+	return evolveResult{
+		ID:    caught.ID,
+		From:  caught.Name(),
+		To:    evolved.Name,
+		Level: evolvedCaught.Level,
+	}, nil
+}
+
+type evolutionNode struct {
+	Species string `json:"species"`
+	// one entry per way of evolving into this species, empty for the first stage
+	Conditions []string        `json:"conditions"`
+	EvolvesTo  []evolutionNode `json:"evolves_to"`
+}
+
+func newEvolutionNode(link pokeapi.ChainLink) evolutionNode {
+	node := evolutionNode{
+		Species:    link.Species.Name,
+		Conditions: []string{},
+		EvolvesTo:  []evolutionNode{},
+	}
+	for _, detail := range link.EvolutionDetails {
+		node.Conditions = append(node.Conditions, pokemon.Describe(detail))
+	}
+	for _, next := range link.EvolvesTo {
+		node.EvolvesTo = append(node.EvolvesTo, newEvolutionNode(next))
+	}
+	return node
+}
+
+type evolutionsResult struct {
+	Pokemon string        `json:"pokemon"`
+	Chain   evolutionNode `json:"chain"`
+}
+
+func (r evolutionsResult) Text(w io.Writer) error {
+	fmt.Fprintln(w, r.Chain.Species)
+	writeEvolutions(w, r.Chain.EvolvesTo, "")
+	return nil
+}
+
+func writeEvolutions(w io.Writer, nodes []evolutionNode, indent string) {
+	for i, node := range nodes {
+		branch, next := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s (%s)\n", indent, branch, node.Species, strings.Join(node.Conditions, " or "))
+		writeEvolutions(w, node.EvolvesTo, indent+next)
+	}
+}
+
+type evolveResult struct {
+	ID    int    `json:"id"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Level int    `json:"level"`
+}
+
+func (r evolveResult) Text(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Congratulations! Your %s evolved into %s!\n", r.From, r.To)
+	return err
+}
@@ -0,0 +1,209 @@
+package battle
+
+import (
+	"math/rand"
+)
+
+// battles that go on longer than this are called a draw
+const maxTurns = 100
+
+type Move struct {
+	Name string
+	Type string
+	// physical or special, status moves never make it into a battle
+	DamageClass string
+	Power       int
+	// 0 means the move never misses
+	Accuracy int
+	Priority int
+}
+
+// Struggle is used by pokemon that don't know any damaging moves
+var Struggle = Move{
+	Name:        "struggle",
+	DamageClass: "physical",
+	Power:       50,
+}
+
+type Stats struct {
+	HP             int
+	Attack         int
+	Defense        int
+	SpecialAttack  int
+	SpecialDefense int
+	Speed          int
+}
+
+type Battler struct {
+	Name  string
+	Level int
+	Types []string
+	Stats Stats
+	HP    int
+	Moves []Move
+}
+
+// MaxHP is the HP of a pokemon at level with a base HP stat of base
+func MaxHP(base, level int) int {
+	return 2*base*level/100 + level + 10
+}
+
+// NewBattler works out the stats of a pokemon at level from its base stats,
+// using the games' formula without IVs, EVs or natures
+func NewBattler(name string, level int, types []string, base Stats, moves []Move) *Battler {
+	stat := func(b int) int {
+		return 2*b*level/100 + 5
+	}
+	stats := Stats{
+		HP:             MaxHP(base.HP, level),
+		Attack:         stat(base.Attack),
+		Defense:        stat(base.Defense),
+		SpecialAttack:  stat(base.SpecialAttack),
+		SpecialDefense: stat(base.SpecialDefense),
+		Speed:          stat(base.Speed),
+	}
+	if len(moves) == 0 {
+		moves = []Move{Struggle}
+	}
+	return &Battler{
+		Name:  name,
+		Level: level,
+		Types: types,
+		Stats: stats,
+		HP:    stats.HP,
+		Moves: moves,
+	}
+}
+
+func (b *Battler) Fainted() bool {
+	return b.HP <= 0
+}
+
+type Event struct {
+	Turn          int     `json:"turn"`
+	Attacker      string  `json:"attacker"`
+	Defender      string  `json:"defender"`
+	Move          string  `json:"move"`
+	Missed        bool    `json:"missed"`
+	Critical      bool    `json:"critical"`
+	Effectiveness float64 `json:"effectiveness"`
+	Damage        int     `json:"damage"`
+	DefenderHP    int     `json:"defender_hp"`
+	Fainted       bool    `json:"fainted"`
+}
+
+type Result struct {
+	// empty when the battle is a draw
+	Winner string  `json:"winner"`
+	Turns  int     `json:"turns"`
+	Events []Event `json:"events"`
+}
+
+// Fight runs a battle until one side faints. every random roll comes from rng,
+// so the same seed always plays out the same battle
+func Fight(a, b *Battler, chart Chart, rng *rand.Rand) Result {
+	var result Result
+	for turn := 1; turn <= maxTurns; turn++ {
+		result.Turns = turn
+		moveA, moveB := bestMove(a, b, chart), bestMove(b, a, chart)
+		first, second := a, b
+		firstMove, secondMove := moveA, moveB
+		if goesSecond(a, b, moveA, moveB, rng) {
+			first, second = b, a
+			firstMove, secondMove = moveB, moveA
+		}
+
+		event := attack(turn, first, second, firstMove, chart, rng)
+		result.Events = append(result.Events, event)
+		if second.Fainted() {
+			result.Winner = first.Name
+			return result
+		}
+		event = attack(turn, second, first, secondMove, chart, rng)
+		result.Events = append(result.Events, event)
+		if first.Fainted() {
+			result.Winner = second.Name
+			return result
+		}
+	}
+	return result
+}
+
+// higher priority moves go first, then the faster pokemon, speed ties are a coin flip
+func goesSecond(a, b *Battler, moveA, moveB Move, rng *rand.Rand) bool {
+	if moveA.Priority != moveB.Priority {
+		return moveA.Priority < moveB.Priority
+	}
+	if a.Stats.Speed != b.Stats.Speed {
+		return a.Stats.Speed < b.Stats.Speed
+	}
+	return rng.Intn(2) == 1
+}
+
+// the move with the highest expected damage, ties go to the first one learned
+func bestMove(attacker, defender *Battler, chart Chart) Move {
+	best := attacker.Moves[0]
+	bestScore := -1.0
+	for _, move := range attacker.Moves {
+		score := float64(move.Power) * stab(attacker, move) * chart.Multiplier(move.Type, defender.Types...)
+		if move.Accuracy > 0 {
+			score *= float64(move.Accuracy) / 100
+		}
+		if score > bestScore {
+			best, bestScore = move, score
+		}
+	}
+	return best
+}
+
+func attack(turn int, attacker, defender *Battler, move Move, chart Chart, rng *rand.Rand) Event {
+	event := Event{
+		Turn:     turn,
+		Attacker: attacker.Name,
+		Defender: defender.Name,
+		Move:     move.Name,
+	}
+	if move.Accuracy > 0 && rng.Intn(100) >= move.Accuracy {
+		event.Missed = true
+		event.DefenderHP = defender.HP
+		return event
+	}
+
+	event.Effectiveness = chart.Multiplier(move.Type, defender.Types...)
+	event.Critical = rng.Intn(24) == 0
+	event.Damage = damage(attacker, defender, move, event.Effectiveness, event.Critical, rng)
+	defender.HP = max(defender.HP-event.Damage, 0)
+	event.DefenderHP = defender.HP
+	event.Fainted = defender.Fainted()
+	return event
+}
+
+// the generation V+ damage formula
+func damage(attacker, defender *Battler, move Move, effectiveness float64, critical bool, rng *rand.Rand) int {
+	if effectiveness == 0 {
+		return 0
+	}
+	atk, def := attacker.Stats.Attack, defender.Stats.Defense
+	if move.DamageClass == "special" {
+		atk, def = attacker.Stats.SpecialAttack, defender.Stats.SpecialDefense
+	}
+
+	base := (2*attacker.Level/5+2)*move.Power*atk/max(def, 1)/50 + 2
+	modifier := stab(attacker, move) * effectiveness
+	if critical {
+		modifier *= 1.5
+	}
+	// the random roll is 85% to 100%
+	modifier *= float64(85+rng.Intn(16)) / 100
+	return max(int(float64(base)*modifier), 1)
+}
+
+// same type attack bonus
+func stab(attacker *Battler, move Move) float64 {
+	for _, t := range attacker.Types {
+		if t == move.Type {
+			return 1.5
+		}
+	}
+	return 1
+}
@@ -0,0 +1,77 @@
+package battle
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func pikachu() *Battler {
+	return NewBattler("pikachu", 50, []string{"electric"}, Stats{35, 55, 40, 50, 50, 90}, []Move{
+		{Name: "thunderbolt", Type: "electric", DamageClass: "special", Power: 90, Accuracy: 100},
+		{Name: "quick-attack", Type: "normal", DamageClass: "physical", Power: 40, Accuracy: 100, Priority: 1},
+	})
+}
+
+func gyarados() *Battler {
+	return NewBattler("gyarados", 50, []string{"water", "flying"}, Stats{95, 125, 79, 60, 100, 81}, []Move{
+		{Name: "aqua-tail", Type: "water", DamageClass: "physical", Power: 90, Accuracy: 90},
+	})
+}
+
+func TestNewBattler(t *testing.T) {
+	p := pikachu()
+	want := Stats{HP: 95, Attack: 60, Defense: 45, SpecialAttack: 55, SpecialDefense: 55, Speed: 95}
+	if p.Stats != want || p.HP != want.HP {
+		t.Errorf("expected %+v, got %+v", want, p.Stats)
+	}
+	if b := NewBattler("magikarp", 5, []string{"water"}, Stats{}, nil); b.Moves[0].Name != "struggle" {
+		t.Errorf("expected a pokemon without moves to struggle, got %v", b.Moves)
+	}
+}
+
+func TestFightIsDeterministic(t *testing.T) {
+	first := Fight(pikachu(), gyarados(), DefaultChart, rand.New(rand.NewSource(7)))
+	second := Fight(pikachu(), gyarados(), DefaultChart, rand.New(rand.NewSource(7)))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave different battles:\n%+v\n%+v", first, second)
+	}
+	if first.Winner == "" {
+		t.Fatal("expected a winner")
+	}
+	last := first.Events[len(first.Events)-1]
+	if !last.Fainted || last.Attacker != first.Winner || last.DefenderHP != 0 {
+		t.Errorf("expected the battle to end with the loser fainting, got %+v", last)
+	}
+}
+
+func TestFightOrderAndEffectiveness(t *testing.T) {
+	result := Fight(pikachu(), gyarados(), DefaultChart, rand.New(rand.NewSource(1)))
+	first := result.Events[0]
+	if first.Attacker != "pikachu" || first.Move != "thunderbolt" {
+		t.Errorf("expected the faster pikachu to open with thunderbolt, got %+v", first)
+	}
+	if first.Effectiveness != 4 && !first.Missed {
+		t.Errorf("expected electric to be 4x against water/flying, got %v", first.Effectiveness)
+	}
+}
+
+func TestImmunity(t *testing.T) {
+	if m := DefaultChart.Multiplier("electric", "ground", "flying"); m != 0 {
+		t.Errorf("expected ground to be immune to electric, got %v", m)
+	}
+	geodude := NewBattler("geodude", 50, []string{"rock", "ground"}, Stats{40, 80, 100, 30, 30, 20}, []Move{
+		{Name: "tackle", Type: "normal", DamageClass: "physical", Power: 40, Accuracy: 100},
+	})
+	p := pikachu()
+	p.Moves = p.Moves[:1]
+	result := Fight(p, geodude, DefaultChart, rand.New(rand.NewSource(3)))
+	for _, event := range result.Events {
+		if event.Attacker == "pikachu" && event.Damage != 0 {
+			t.Errorf("thunderbolt should not hurt geodude: %+v", event)
+		}
+	}
+	if result.Winner != "geodude" {
+		t.Errorf("expected geodude to win, got %q", result.Winner)
+	}
+}
@@ -0,0 +1,174 @@
+// Package encounter turns a location area's encounter tables into wild pokemon.
+package encounter
+
+import (
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"math/rand"
+)
+
+var (
+	ErrNotHere = errors.New("not found in this area")
+	// no slots matched the method and version
+	ErrNoEncounters = errors.New("no encounters")
+)
+
+// DefaultMethod is walking through tall grass
+const DefaultMethod = "walk"
+
+// Slot is one row of an area's encounter table: a pokemon that shows up
+// through a method, in a version, at a range of levels
+type Slot struct {
+	Pokemon string
+	Version string
+	Method  string
+	// percent of the encounters through Method that are this slot
+	Chance   int
+	MinLevel int
+	MaxLevel int
+}
+
+// Slots flattens the encounter tables of an area
+func Slots(area pokeapi.LocationArea) []Slot {
+	var slots []Slot
+	for _, encounter := range area.PokemonEncounters {
+		for _, version := range encounter.VersionDetails {
+			for _, detail := range version.EncounterDetails {
+				slots = append(slots, Slot{
+					Pokemon:  encounter.Pokemon.Name,
+					Version:  version.Version.Name,
+					Method:   detail.Method.Name,
+					Chance:   detail.Chance,
+					MinLevel: detail.MinLevel,
+					MaxLevel: detail.MaxLevel,
+				})
+			}
+		}
+	}
+	return slots
+}
+
+// Wild is a wild pokemon that showed up
+type Wild struct {
+	Pokemon string `json:"pokemon"`
+	Method  string `json:"method"`
+	Level   int    `json:"level"`
+	Chance  int    `json:"chance"`
+}
+
+// Pokemon lists the pokemon found in an area in a version, every version
+// when it's empty
+func Pokemon(area pokeapi.LocationArea, version string) []string {
+	var names []string
+	for _, encounter := range area.PokemonEncounters {
+		for _, details := range encounter.VersionDetails {
+			if version == "" || details.Version.Name == version {
+				names = append(names, encounter.Pokemon.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// Look searches an area in a version for a pokemon, using the method it's
+// most likely to show up through. appeared is false when it's there but
+// didn't show up this time, and the error wraps ErrNotHere when it's never
+// there. an empty version is the first one listed for the pokemon
+func Look(area pokeapi.LocationArea, pokemon, version string, rng *rand.Rand) (wild Wild, appeared bool, err error) {
+	var best *Slot
+	slots := Slots(area)
+	for i, slot := range slots {
+		if slot.Pokemon != pokemon || (version != "" && slot.Version != version) || (best != nil && slot.Version != best.Version) {
+			continue
+		}
+		if best == nil || slot.Chance > best.Chance {
+			best = &slots[i]
+		}
+	}
+	if best == nil {
+		return Wild{}, false, fmt.Errorf("%s: %w", pokemon, ErrNotHere)
+	}
+
+	wild = Wild{
+		Pokemon: pokemon,
+		Method:  best.Method,
+		Level:   level(*best, rng),
+		Chance:  best.Chance,
+	}
+	return wild, rng.Intn(100) < best.Chance, nil
+}
+
+func level(slot Slot, rng *rand.Rand) int {
+	if slot.MaxLevel <= slot.MinLevel {
+		return slot.MinLevel
+	}
+	return slot.MinLevel + rng.Intn(slot.MaxLevel-slot.MinLevel+1)
+}
+
+// Filter narrows the slots an encounter is drawn from. an empty Version is
+// the first version the area lists, an empty Method is DefaultMethod
+type Filter struct {
+	Version string
+	Method  string
+}
+
+func (f Filter) normalize(slots []Slot) Filter {
+	if f.Method == "" {
+		f.Method = DefaultMethod
+	}
+	if f.Version == "" && len(slots) > 0 {
+		f.Version = slots[0].Version
+	}
+	return f
+}
+
+// Methods lists the encounter methods an area has in a version, in the
+// order they first appear
+func Methods(area pokeapi.LocationArea, version string) []string {
+	slots := Slots(area)
+	version = Filter{Version: version}.normalize(slots).Version
+	var methods []string
+	seen := make(map[string]bool)
+	for _, slot := range slots {
+		if slot.Version == version && !seen[slot.Method] {
+			seen[slot.Method] = true
+			methods = append(methods, slot.Method)
+		}
+	}
+	return methods
+}
+
+// Sample draws a wild pokemon from an area's encounter table, each slot
+// weighted by its chance
+func Sample(area pokeapi.LocationArea, filter Filter, rng *rand.Rand) (Wild, error) {
+	slots := Slots(area)
+	filter = filter.normalize(slots)
+	var matching []Slot
+	total := 0
+	for _, slot := range slots {
+		if slot.Version == filter.Version && slot.Method == filter.Method && slot.Chance > 0 {
+			matching = append(matching, slot)
+			total += slot.Chance
+		}
+	}
+	if total == 0 {
+		return Wild{}, fmt.Errorf("%s in %s: %w", filter.Method, filter.Version, ErrNoEncounters)
+	}
+
+	roll := rng.Intn(total)
+	for _, slot := range matching {
+		if roll < slot.Chance {
+			return Wild{
+				Pokemon: slot.Pokemon,
+				Method:  slot.Method,
+				Level:   level(slot, rng),
+				Chance:  slot.Chance,
+			}, nil
+		}
+		roll -= slot.Chance
+	}
+	// unreachable, the rolls add up to total
+	return Wild{}, ErrNoEncounters
+}
@@ -0,0 +1,116 @@
+package encounter
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"math/rand"
+	"testing"
+)
+
+const canalave = `{"name":"canalave-city-area","pokemon_encounters":[
+	{"pokemon":{"name":"tentacool"},"version_details":[
+		{"version":{"name":"diamond"},"encounter_details":[{"method":{"name":"surf"},"chance":60,"min_level":20,"max_level":30}]},
+		{"version":{"name":"pearl"},"encounter_details":[{"method":{"name":"surf"},"chance":60,"min_level":40,"max_level":40}]}]},
+	{"pokemon":{"name":"magikarp"},"version_details":[
+		{"version":{"name":"diamond"},"encounter_details":[
+			{"method":{"name":"old-rod"},"chance":100,"min_level":3,"max_level":15},
+			{"method":{"name":"good-rod"},"chance":55,"min_level":10,"max_level":25}]}]}]}`
+
+func testArea(t *testing.T) pokeapi.LocationArea {
+	t.Helper()
+	var area pokeapi.LocationArea
+	err := json.Unmarshal([]byte(canalave), &area)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return area
+}
+
+func TestLook(t *testing.T) {
+	area := testArea(t)
+	rng := rand.New(rand.NewSource(1))
+
+	if len(Slots(area)) != 4 {
+		t.Errorf("expected 4 slots, got %d", len(Slots(area)))
+	}
+	_, _, err := Look(area, "pikachu", "", rng)
+	if !errors.Is(err, ErrNotHere) {
+		t.Errorf("expected ErrNotHere, got %v", err)
+	}
+
+	appeared := 0
+	for i := 0; i < 100; i++ {
+		wild, ok, err := Look(area, "tentacool", "", rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wild.Method != "surf" || wild.Level < 20 || wild.Level > 30 {
+			t.Fatalf("unexpected wild pokemon: %+v", wild)
+		}
+		if ok {
+			appeared++
+		}
+	}
+	if appeared < 40 || appeared > 80 {
+		t.Errorf("tentacool has a 60%% chance but showed up %d times in 100", appeared)
+	}
+
+	// magikarp is always looked for with its best method
+	wild, ok, err := Look(area, "magikarp", "", rng)
+	if err != nil || !ok || wild.Method != "old-rod" {
+		t.Errorf("expected magikarp on the old rod, got %+v %v %v", wild, ok, err)
+	}
+
+	// pearl only has tentacool
+	_, _, err = Look(area, "magikarp", "pearl", rng)
+	if !errors.Is(err, ErrNotHere) {
+		t.Errorf("expected no magikarp in pearl, got %v", err)
+	}
+	wild, _, err = Look(area, "tentacool", "pearl", rng)
+	if err != nil || wild.Level != 40 {
+		t.Errorf("expected the pearl tentacool, got %+v %v", wild, err)
+	}
+	if names := Pokemon(area, "pearl"); len(names) != 1 || names[0] != "tentacool" {
+		t.Errorf("expected only tentacool in pearl, got %v", names)
+	}
+	if names := Pokemon(area, ""); len(names) != 2 {
+		t.Errorf("expected both pokemon in every version, got %v", names)
+	}
+}
+
+func TestSample(t *testing.T) {
+	area := testArea(t)
+	rng := rand.New(rand.NewSource(1))
+
+	_, err := Sample(area, Filter{}, rng)
+	if !errors.Is(err, ErrNoEncounters) {
+		t.Errorf("expected ErrNoEncounters walking on water, got %v", err)
+	}
+	_, err = Sample(area, Filter{Version: "platinum", Method: "surf"}, rng)
+	if !errors.Is(err, ErrNoEncounters) {
+		t.Errorf("expected ErrNoEncounters in a version the area doesn't list, got %v", err)
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		wild, err := Sample(area, Filter{Method: "good-rod"}, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[wild.Pokemon]++
+	}
+	if counts["magikarp"] != 1000 {
+		t.Errorf("expected only magikarp on the good rod, got %v", counts)
+	}
+
+	if methods := Methods(area, ""); len(methods) != 3 || methods[0] != "surf" || methods[2] != "good-rod" {
+		t.Errorf("unexpected methods: %v", methods)
+	}
+
+	first, _ := Sample(area, Filter{Method: "old-rod"}, rand.New(rand.NewSource(7)))
+	second, _ := Sample(area, Filter{Method: "old-rod"}, rand.New(rand.NewSource(7)))
+	if first != second {
+		t.Errorf("same seed gave different encounters: %+v %+v", first, second)
+	}
+}
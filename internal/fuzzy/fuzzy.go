@@ -0,0 +1,65 @@
+// Package fuzzy finds the names closest to a misspelled one.
+package fuzzy
+
+import (
+	"sort"
+)
+
+// Distance is how many single letter insertions, deletions, substitutions
+// and swaps of neighbouring letters it takes to turn a into b
+func Distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// rows i-2, i-1 and i of the table
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	row := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		row[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			row[j] = min(prev[j]+1, row[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				row[j] = min(row[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, row = prev, row, prev2
+	}
+	return prev[len(t)]
+}
+
+// Threshold is how far a name can be from word and still be suggested,
+// about a third of its letters
+func Threshold(word string) int {
+	return max(1, (len([]rune(word))+2)/3)
+}
+
+// Suggest returns the candidates at most maxDistance away from word,
+// closest first and alphabetically when they're as close
+func Suggest(word string, candidates []string, maxDistance int) []string {
+	distances := make(map[string]int)
+	var matches []string
+	for _, candidate := range candidates {
+		if _, ok := distances[candidate]; ok {
+			continue
+		}
+		d := Distance(word, candidate)
+		distances[candidate] = d
+		if d <= maxDistance {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if distances[a] != distances[b] {
+			return distances[a] < distances[b]
+		}
+		return a < b
+	})
+	return matches
+}
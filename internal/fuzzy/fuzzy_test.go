@@ -0,0 +1,46 @@
+package fuzzy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"map", "map", 0},
+		{"", "map", 3},
+		{"mapb", "map", 1},
+		{"cacth", "catch", 1},
+		{"pikachu", "pickachu", 1},
+		{"kitten", "sitting", 3},
+		{"pokédex", "pokedex", 1},
+	}
+	for _, c := range cases {
+		if got := Distance(c.a, c.b); got != c.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", c.a, c.b, got, c.want)
+		}
+		if got := Distance(c.b, c.a); got != c.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", c.b, c.a, got, c.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	commands := []string{"bag", "battle", "catch", "counter", "map", "mapb", "matchup"}
+	cases := map[string][]string{
+		"cacth":  {"catch"},
+		"ba":     {"bag"},
+		"mpa":    {"map"},
+		"xyzzy":  nil,
+		"battel": {"battle"},
+	}
+	for word, want := range cases {
+		if got := Suggest(word, commands, Threshold(word)); !slices.Equal(got, want) {
+			t.Errorf("Suggest(%q) = %v, want %v", word, got, want)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+// Package lineedit reads lines from a terminal with editing, history and
+// tab completion. when the input isn't a terminal lines are read as they are.
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// ErrInterrupted is returned by ReadLine when Ctrl-C is pressed
+var ErrInterrupted = errors.New("interrupted")
+
+// MaxHistory is how many lines are kept in the history file
+const MaxHistory = 1000
+
+// CompleteFunc gets the line up to the cursor and returns the words that
+// could take the place of its last word
+type CompleteFunc func(line string) []string
+
+type Editor struct {
+	Complete CompleteFunc
+	in       *bufio.Reader
+	out      io.Writer
+	// -1 when the input isn't a file
+	fd          int
+	history     []string
+	historyPath string
+}
+
+// New makes an editor reading from in, with its history kept at
+// historyPath. an empty historyPath keeps history in memory only
+func New(in io.Reader, out io.Writer, historyPath string) *Editor {
+	e := &Editor{
+		in:          bufio.NewReader(in),
+		out:         out,
+		fd:          -1,
+		historyPath: historyPath,
+	}
+	if f, ok := in.(*os.File); ok {
+		e.fd = int(f.Fd())
+	}
+	e.loadHistory()
+	return e
+}
+
+// ReadLine prints the prompt and reads a line. it returns io.EOF at the
+// end of the input, or on Ctrl-D with nothing typed
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	restore, err := makeRaw(e.fd)
+	if err != nil {
+		// not a terminal, so there's nothing to edit
+		fmt.Fprint(e.out, prompt)
+		return e.readPlain()
+	}
+	defer restore()
+	return e.edit(prompt)
+}
+
+func (e *Editor) readPlain() (string, error) {
+	line, err := e.in.ReadString('\n')
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// the line being edited
+type state struct {
+	prompt string
+	buf    []rune
+	pos    int
+	// the history entry being shown, len(history) is the new line
+	index int
+	// what was typed before moving through the history
+	pending []rune
+	// the last key was a tab that had more than one completion
+	tabbed bool
+}
+
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyBackspace = 8
+	keyTab       = 9
+	keyNewline   = 10
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+func (e *Editor) edit(prompt string) (string, error) {
+	s := &state{prompt: prompt, index: len(e.history)}
+	e.refresh(s)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(s.buf) > 0 {
+				break
+			}
+			return "", err
+		}
+		tabbed := false
+		switch r {
+		case keyEnter, keyNewline:
+			fmt.Fprint(e.out, "\r\n")
+			return string(s.buf), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", ErrInterrupted
+		case keyCtrlD:
+			if len(s.buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			s.deleteAt(s.pos)
+		case keyTab:
+			tabbed = e.complete(s)
+		case keyBackspace, keyDelete:
+			if s.pos > 0 {
+				s.pos--
+				s.deleteAt(s.pos)
+			}
+		case keyCtrlA:
+			s.pos = 0
+		case keyCtrlE:
+			s.pos = len(s.buf)
+		case keyCtrlB:
+			s.pos = max(s.pos-1, 0)
+		case keyCtrlF:
+			s.pos = min(s.pos+1, len(s.buf))
+		case keyCtrlK:
+			s.buf = s.buf[:s.pos]
+		case keyCtrlU:
+			s.buf = append([]rune{}, s.buf[s.pos:]...)
+			s.pos = 0
+		case keyCtrlW:
+			start := wordStart(s.buf, s.pos)
+			s.buf = append(s.buf[:start], s.buf[s.pos:]...)
+			s.pos = start
+		case keyCtrlP:
+			e.moveHistory(s, -1)
+		case keyCtrlN:
+			e.moveHistory(s, 1)
+		case keyCtrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case keyEscape:
+			e.escape(s)
+		default:
+			if unicode.IsPrint(r) {
+				s.insert(r)
+			}
+		}
+		s.tabbed = tabbed
+		e.refresh(s)
+	}
+	fmt.Fprint(e.out, "\r\n")
+	return string(s.buf), nil
+}
+
+// escape handles the arrow keys and the rest of the keys that send an
+// escape sequence, like "\x1b[A" for up
+func (e *Editor) escape(s *state) {
+	next, _, err := e.in.ReadRune()
+	if err != nil || (next != '[' && next != 'O') {
+		return
+	}
+	key, _, err := e.in.ReadRune()
+	if err != nil {
+		return
+	}
+	if key >= '0' && key <= '9' {
+		// "\x1b[3~" is delete, "\x1b[1~" and "\x1b[4~" home and end
+		tilde, _, err := e.in.ReadRune()
+		if err != nil || tilde != '~' {
+			return
+		}
+		switch key {
+		case '3':
+			s.deleteAt(s.pos)
+		case '1', '7':
+			s.pos = 0
+		case '4', '8':
+			s.pos = len(s.buf)
+		}
+		return
+	}
+	switch key {
+	case 'A':
+		e.moveHistory(s, -1)
+	case 'B':
+		e.moveHistory(s, 1)
+	case 'C':
+		s.pos = min(s.pos+1, len(s.buf))
+	case 'D':
+		s.pos = max(s.pos-1, 0)
+	case 'H':
+		s.pos = 0
+	case 'F':
+		s.pos = len(s.buf)
+	}
+}
+
+func (s *state) insert(r rune) {
+	s.buf = append(s.buf, 0)
+	copy(s.buf[s.pos+1:], s.buf[s.pos:])
+	s.buf[s.pos] = r
+	s.pos++
+}
+
+func (s *state) deleteAt(i int) {
+	if i < len(s.buf) {
+		s.buf = append(s.buf[:i], s.buf[i+1:]...)
+	}
+}
+
+func wordStart(buf []rune, pos int) int {
+	start := pos
+	for start > 0 && buf[start-1] == ' ' {
+		start--
+	}
+	for start > 0 && buf[start-1] != ' ' {
+		start--
+	}
+	return start
+}
+
+// redraws the line and puts the cursor back where it was
+func (e *Editor) refresh(s *state) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", s.prompt, string(s.buf))
+	if back := len(s.buf) - s.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+func (e *Editor) moveHistory(s *state, step int) {
+	index := s.index + step
+	if index < 0 || index > len(e.history) {
+		return
+	}
+	if s.index == len(e.history) {
+		s.pending = append([]rune{}, s.buf...)
+	}
+	s.index = index
+	if index == len(e.history) {
+		s.buf = append([]rune{}, s.pending...)
+	} else {
+		s.buf = []rune(e.history[index])
+	}
+	s.pos = len(s.buf)
+}
+
+// complete fills in the word before the cursor. with one match the word is
+// finished, with more the part they share is filled in and a second tab
+// lists them. it reports whether there was more than one match
+func (e *Editor) complete(s *state) bool {
+	if e.Complete == nil {
+		return false
+	}
+	start := wordStart(s.buf, s.pos)
+	if start < s.pos && s.buf[s.pos-1] == ' ' {
+		start = s.pos
+	}
+	word := string(s.buf[start:s.pos])
+	var matches []string
+	for _, candidate := range e.Complete(string(s.buf[:s.pos])) {
+		if strings.HasPrefix(candidate, word) {
+			matches = append(matches, candidate)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		fmt.Fprint(e.out, "\a")
+		return false
+	case 1:
+		e.replaceWord(s, start, matches[0]+" ")
+		return false
+	}
+	prefix := commonPrefix(matches)
+	if len(prefix) > len(word) {
+		e.replaceWord(s, start, prefix)
+		return true
+	}
+	if s.tabbed {
+		fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(matches, "  "))
+	} else {
+		fmt.Fprint(e.out, "\a")
+	}
+	return true
+}
+
+func (e *Editor) replaceWord(s *state, start int, word string) {
+	rest := append([]rune(word), s.buf[s.pos:]...)
+	s.buf = append(s.buf[:start], rest...)
+	s.pos = start + len([]rune(word))
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// AddHistory remembers a line, skipping blank lines and repeats of the
+// last one, and appends it to the history file
+func (e *Editor) AddHistory(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return nil
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > MaxHistory {
+		e.history = e.history[len(e.history)-MaxHistory:]
+	}
+	if e.historyPath == "" {
+		return nil
+	}
+	err := os.MkdirAll(filepath.Dir(e.historyPath), 0o755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(e.historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(f, line)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// History returns the remembered lines, oldest first
+func (e *Editor) History() []string {
+	return append([]string{}, e.history...)
+}
+
+// loads the last MaxHistory lines and rewrites the file when it has grown
+// past twice that
+func (e *Editor) loadHistory() {
+	if e.historyPath == "" {
+		return
+	}
+	body, err := os.ReadFile(e.historyPath)
+	if err != nil {
+		return
+	}
+	lines := strings.Split(strings.TrimRight(string(body), "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return
+	}
+	if len(lines) > MaxHistory {
+		grown := len(lines) > 2*MaxHistory
+		lines = lines[len(lines)-MaxHistory:]
+		if grown {
+			os.WriteFile(e.historyPath, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+		}
+	}
+	e.history = lines
+}
@@ -0,0 +1,119 @@
+package lineedit
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEdit(t *testing.T) {
+	cases := map[string]string{
+		"catch\r":                      "catch",
+		"cach\x1b[D\x1b[Dt\r":          "catch",
+		"pikachu\x01inspect \r":        "inspect pikachu",
+		"explore canalave\x17goto\r":   "explore goto",
+		"battle\x7f\x7f\x7f\x7f\x7f\r": "b",
+		"help me\x1b[D\x1b[D\x0b\r":    "help ",
+		"mapb\x02\x1b[3~\r":            "map",
+		"exit\x15map\r":                "map",
+		"pokédex\r":                    "pokédex",
+	}
+	for in, want := range cases {
+		e := New(strings.NewReader(in), io.Discard, "")
+		got, err := e.edit("> ")
+		if err != nil || got != want {
+			t.Errorf("%q: expected %q, got %q %v", in, want, got, err)
+		}
+	}
+
+	e := New(strings.NewReader("\x04"), io.Discard, "")
+	if _, err := e.edit("> "); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF on Ctrl-D, got %v", err)
+	}
+	e = New(strings.NewReader("map\x03"), io.Discard, "")
+	if _, err := e.edit("> "); !errors.Is(err, ErrInterrupted) {
+		t.Errorf("expected ErrInterrupted on Ctrl-C, got %v", err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	e := New(strings.NewReader("\x1b[A\x1b[A\r\x1b[A\x1b[A\x1b[B\x1b[Bexp\x1b[A\x1b[B\r"), io.Discard, path)
+	for _, line := range []string{"map", "", "pokedex", "pokedex", "  bag "} {
+		e.AddHistory(line)
+	}
+	if history := e.History(); !slices.Equal(history, []string{"map", "pokedex", "bag"}) {
+		t.Fatalf("unexpected history: %v", history)
+	}
+
+	// up twice goes back to pokedex
+	if line, _ := e.edit("> "); line != "pokedex" {
+		t.Errorf("expected pokedex, got %q", line)
+	}
+	// going down past the newest line brings back what was typed
+	if line, _ := e.edit("> "); line != "exp" {
+		t.Errorf("expected exp, got %q", line)
+	}
+
+	// the history outlives the editor
+	e = New(strings.NewReader(""), io.Discard, path)
+	if history := e.History(); !slices.Equal(history, []string{"map", "pokedex", "bag"}) {
+		t.Errorf("unexpected history after loading: %v", history)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	complete := func(line string) []string {
+		if !strings.Contains(line, " ") {
+			return []string{"map", "mapb", "matchup", "evolve", "evolutions"}
+		}
+		return []string{"pikachu", "magikarp"}
+	}
+	cases := map[string]string{
+		"ev\t\r":                 "evol",
+		"evolu\t\r":              "evolutions ",
+		"ma\t\r":                 "ma",
+		"mat\tpi\t\r":            "matchup pikachu ",
+		"catch \t\r":             "catch ",
+		"catch mag\t\r":          "catch magikarp ",
+		"x\t\r":                  "x",
+		"mat pi\x02\x02\x02\t\r": "matchup  pi",
+	}
+	for in, want := range cases {
+		e := New(strings.NewReader(in), io.Discard, "")
+		e.Complete = complete
+		got, err := e.edit("> ")
+		if err != nil || got != want {
+			t.Errorf("%q: expected %q, got %q %v", in, want, got, err)
+		}
+	}
+
+	// a second tab lists the matches
+	var out strings.Builder
+	e := New(strings.NewReader("map\t\t\r"), &out, "")
+	e.Complete = complete
+	e.edit("> ")
+	if !strings.Contains(out.String(), "map  mapb") {
+		t.Errorf("expected the matches listed, got %q", out.String())
+	}
+}
+
+func TestReadLinePlain(t *testing.T) {
+	var out strings.Builder
+	e := New(strings.NewReader("map\nexplore canalave-city-area\r\nexit"), &out, "")
+	for _, want := range []string{"map", "explore canalave-city-area", "exit"} {
+		got, err := e.ReadLine("> ")
+		if err != nil || got != want {
+			t.Errorf("expected %q, got %q %v", want, got, err)
+		}
+	}
+	if _, err := e.ReadLine("> "); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+	if out.String() != "> > > > " {
+		t.Errorf("expected a prompt per line, got %q", out.String())
+	}
+}
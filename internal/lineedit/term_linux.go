@@ -0,0 +1,10 @@
+package lineedit
+
+import (
+	"syscall"
+)
+
+const (
+	ioctlGetTermios = syscall.TCGETS
+	ioctlSetTermios = syscall.TCSETS
+)
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package lineedit
+
+import (
+	"errors"
+)
+
+// there's no raw mode here, lines are read without editing
+func makeRaw(fd int) (restore func(), err error) {
+	return nil, errors.New("line editing isn't supported on this platform")
+}
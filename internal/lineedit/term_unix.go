@@ -0,0 +1,49 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package lineedit
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+func getTermios(fd int) (*syscall.Termios, error) {
+	var t syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), ioctlGetTermios, uintptr(unsafe.Pointer(&t)))
+	if errno != 0 {
+		return nil, errno
+	}
+	return &t, nil
+}
+
+func setTermios(fd int, t *syscall.Termios) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), ioctlSetTermios, uintptr(unsafe.Pointer(t)))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// makeRaw turns off echo, line buffering and signal keys so every key
+// reaches the editor. it fails when fd isn't a terminal
+func makeRaw(fd int) (restore func(), err error) {
+	if fd < 0 {
+		return nil, errors.New("not a terminal")
+	}
+	old, err := getTermios(fd)
+	if err != nil {
+		return nil, err
+	}
+	raw := *old
+	raw.Iflag &^= syscall.BRKINT | syscall.ICRNL | syscall.INPCK | syscall.ISTRIP | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON | syscall.IEXTEN | syscall.ISIG
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+	err = setTermios(fd, &raw)
+	if err != nil {
+		return nil, err
+	}
+	return func() { setTermios(fd, old) }, nil
+}
@@ -0,0 +1,131 @@
+package pokeapi
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/srijan-raghavula/pokedex/internal/pokecache"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const DefaultBaseURL = "https://pokeapi.co/api/v2"
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	cache      pokecache.Cache
+	retry      RetryPolicy
+}
+
+func NewClient(baseURL string, timeout time.Duration, cache pokecache.Cache) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		cache: cache,
+		retry: DefaultRetryPolicy,
+	}
+}
+
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// SetTransport swaps the network for something else, like an offline snapshot
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// URL joins path segments onto the base url, URL("pokemon", "pikachu")
+func (c *Client) URL(path ...string) string {
+	return c.baseURL + "/" + strings.Join(path, "/")
+}
+
+// Get returns the body at url, from the cache when possible.
+// transient failures are retried until the retry policy's deadline
+func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
+	val, ok := c.cache.Get(url)
+	if ok {
+		return val, nil
+	}
+	body, err := c.Fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Add(url, body)
+	return body, nil
+}
+
+// Fetch is Get without the cache, for reading every resource once like a
+// snapshot does, which would only push everything else out of it
+func (c *Client) Fetch(ctx context.Context, url string) ([]byte, error) {
+	if c.retry.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.retry.Deadline)
+		defer cancel()
+	}
+
+	for attempt := 0; ; attempt++ {
+		body, header, err := c.fetch(ctx, url)
+		if err == nil {
+			return body, nil
+		}
+		if attempt+1 >= c.retry.MaxAttempts || !retryable(ctx, err) {
+			return nil, err
+		}
+
+		wait := c.retry.delay(attempt, header)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			// no point waiting if the deadline hits first
+			return nil, err
+		}
+		if sleep(ctx, wait) != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) fetch(ctx context.Context, url string) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+
+	if sc := res.StatusCode; sc > 299 {
+		statusErr := &StatusError{URL: url, StatusCode: sc}
+		if strings.HasPrefix(res.Header.Get("Content-Type"), "text/plain") {
+			// enough for a sentence, not a whole error page
+			message, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+			// a body that only repeats the status says less than the url
+			if text := strings.TrimSpace(string(message)); text != http.StatusText(sc) {
+				statusErr.Message = text
+			}
+		}
+		return nil, res.Header, statusErr
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return body, res.Header, nil
+}
+
+func (c *Client) GetJSON(ctx context.Context, url string, v any) error {
+	body, err := c.Get(ctx, url)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,227 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"github.com/srijan-raghavula/pokedex/internal/pokecache"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetCaches(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"name":"canalave-city-area"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Second, pokecache.NewCache(time.Minute))
+	for i := 0; i < 2; i++ {
+		area, err := client.LocationArea(context.Background(), "canalave-city-area")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if area.Name != "canalave-city-area" {
+			t.Errorf("unexpected name: %s", area.Name)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("expected 1 request, got %d", hits)
+	}
+}
+
+func TestFetchSkipsCache(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"name":"canalave-city-area"}`))
+	}))
+	defer server.Close()
+
+	cache := pokecache.NewCache(time.Minute)
+	client := NewClient(server.URL, time.Second, cache)
+	url := client.URL("location-area", "canalave-city-area")
+	for i := 0; i < 2; i++ {
+		_, err := client.Fetch(context.Background(), url)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if hits != 2 {
+		t.Errorf("expected 2 requests, got %d", hits)
+	}
+	if _, ok := cache.Get(url); ok {
+		t.Errorf("expected Fetch to leave the cache alone")
+	}
+}
+
+func TestStatusErrors(t *testing.T) {
+	cases := []struct {
+		statusCode int
+		want       error
+	}{
+		{statusCode: http.StatusNotFound, want: ErrNotFound},
+		{statusCode: http.StatusTooManyRequests, want: ErrRateLimited},
+		{statusCode: http.StatusBadGateway, want: ErrServer},
+	}
+
+	for _, testCase := range cases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(testCase.statusCode)
+		}))
+		client := NewClient(server.URL, time.Second, pokecache.NewCache(time.Minute))
+		client.SetRetryPolicy(testRetryPolicy())
+		_, err := client.Get(context.Background(), client.URL("pokemon", "missingno"))
+		server.Close()
+
+		if !errors.Is(err, testCase.want) {
+			t.Errorf("status %d: expected %v, got %v", testCase.statusCode, testCase.want, err)
+		}
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != testCase.statusCode {
+			t.Errorf("status %d: expected a StatusError, got %v", testCase.statusCode, err)
+		}
+	}
+}
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond * 5,
+		Deadline:    time.Second,
+	}
+}
+
+func TestRetry(t *testing.T) {
+	cases := []struct {
+		name     string
+		statuses []int
+		header   string
+		wantErr  error
+		wantHits int
+	}{
+		{name: "server error then ok", statuses: []int{503, 502, 200}, wantHits: 3},
+		{name: "rate limited with retry-after", statuses: []int{429, 200}, header: "0", wantHits: 2},
+		{name: "gives up after max attempts", statuses: []int{500, 500, 500, 200}, wantErr: ErrServer, wantHits: 3},
+		{name: "not found is not retried", statuses: []int{404, 200}, wantErr: ErrNotFound, wantHits: 1},
+		{name: "retry-after past the deadline", statuses: []int{429, 200}, header: "60", wantErr: ErrRateLimited, wantHits: 1},
+	}
+
+	for _, testCase := range cases {
+		hits := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status := testCase.statuses[hits]
+			hits++
+			if testCase.header != "" {
+				w.Header().Set("Retry-After", testCase.header)
+			}
+			w.WriteHeader(status)
+			w.Write([]byte(`{}`))
+		}))
+		client := NewClient(server.URL, time.Second, pokecache.NewCache(time.Minute))
+		client.SetRetryPolicy(testRetryPolicy())
+		_, err := client.Get(context.Background(), client.URL("pokemon", "pikachu"))
+		server.Close()
+
+		if testCase.wantErr == nil && err != nil {
+			t.Errorf("%s: unexpected error: %v", testCase.name, err)
+		}
+		if testCase.wantErr != nil && !errors.Is(err, testCase.wantErr) {
+			t.Errorf("%s: expected %v, got %v", testCase.name, testCase.wantErr, err)
+		}
+		if hits != testCase.wantHits {
+			t.Errorf("%s: expected %d requests, got %d", testCase.name, testCase.wantHits, hits)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "3")
+	wait, ok := retryAfter(header)
+	if !ok || wait != time.Second*3 {
+		t.Errorf("expected 3s, got %v", wait)
+	}
+
+	header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	wait, ok = retryAfter(header)
+	if !ok || wait < time.Second*55 || wait > time.Minute {
+		t.Errorf("expected about a minute, got %v", wait)
+	}
+
+	header.Set("Retry-After", "soon")
+	_, ok = retryAfter(header)
+	if ok {
+		t.Errorf("expected an invalid Retry-After to be ignored")
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	policy := testRetryPolicy()
+	for attempt := 0; attempt < 70; attempt++ {
+		d := policy.delay(attempt, http.Header{})
+		if d < 0 || d > policy.MaxDelay {
+			t.Errorf("attempt %d: delay %v out of bounds", attempt, d)
+		}
+	}
+}
+
+func TestStatusErrorMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pokemon/mew" {
+			http.Error(w, "pokemon/mew is not in the offline snapshot", http.StatusNotFound)
+			return
+		}
+		// what PokeAPI answers
+		http.Error(w, "Not Found", http.StatusNotFound)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, time.Second, pokecache.NewCache(time.Minute))
+	client.SetRetryPolicy(testRetryPolicy())
+
+	_, err := client.Get(context.Background(), client.URL("pokemon", "mew"))
+	if !errors.Is(err, ErrNotFound) || err.Error() != "pokemon/mew is not in the offline snapshot (response status code: 404)" {
+		t.Errorf("expected the reason for the 404, got %v", err)
+	}
+	// a body that only says "Not Found" leaves the url in the message
+	_, err = client.Get(context.Background(), client.URL("pokemon", "missingno"))
+	if err == nil || err.Error() != "response status code: 404 ("+server.URL+"/pokemon/missingno)" {
+		t.Errorf("expected the url of the 404, got %v", err)
+	}
+}
+
+// a transport that fails every request without touching the network, like
+// an offline snapshot that can't read its archive
+type failingTransport struct {
+	hits *int
+}
+
+func (f failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	*f.hits++
+	return nil, errors.New("the archive is corrupt")
+}
+
+func TestRetryTransportErrors(t *testing.T) {
+	hits := 0
+	client := NewClient("http://pokeapi.test/api/v2", time.Second, pokecache.NewCache(time.Minute))
+	client.SetRetryPolicy(testRetryPolicy())
+	client.SetTransport(failingTransport{hits: &hits})
+	_, err := client.Get(context.Background(), client.URL("pokemon", "mew"))
+	if err == nil || hits != 1 {
+		t.Errorf("expected a transport error to fail the first time, got %v after %d requests", err, hits)
+	}
+
+	// nothing listens on port 1, a network error worth retrying
+	client = NewClient("http://127.0.0.1:1/api/v2", time.Second, pokecache.NewCache(time.Minute))
+	client.SetRetryPolicy(testRetryPolicy())
+	var netErr net.Error
+	_, err = client.Get(context.Background(), client.URL("pokemon", "mew"))
+	if !errors.As(err, &netErr) || !retryable(context.Background(), err) {
+		t.Errorf("expected a refused connection to be retried, got %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultLimit is how many results PokeAPI pages hold unless asked otherwise
+const DefaultLimit = 20
+
+var (
+	ErrFirstPage = errors.New("already on the first page")
+	ErrLastPage  = errors.New("already on the last page")
+)
+
+// Cursor keeps its place in one of PokeAPI's list endpoints, like
+// "location-area" or "pokemon". pages count from 1, and are fetched by
+// offset so any page can be jumped to
+type Cursor struct {
+	client   *Client
+	resource string
+	limit    int
+	// the page last fetched, 0 until the first fetch
+	page int
+	// how many results there are, known after the first fetch
+	count int
+}
+
+func (c *Client) Cursor(resource string, limit int) *Cursor {
+	if limit < 1 {
+		limit = DefaultLimit
+	}
+	return &Cursor{client: c, resource: resource, limit: limit}
+}
+
+func (cur *Cursor) Resource() string {
+	return cur.resource
+}
+
+func (cur *Cursor) Limit() int {
+	return cur.limit
+}
+
+func (cur *Cursor) Page() int {
+	return cur.page
+}
+
+// Pages is how many pages there are, 0 before the first fetch
+func (cur *Cursor) Pages() int {
+	if cur.page == 0 {
+		return 0
+	}
+	return max(1, (cur.count+cur.limit-1)/cur.limit)
+}
+
+// Count is how many results there are over every page, 0 before the first fetch
+func (cur *Cursor) Count() int {
+	return cur.count
+}
+
+// SetLimit changes the page size, staying on the page that holds the
+// first result of the current one
+func (cur *Cursor) SetLimit(limit int) {
+	if limit < 1 || limit == cur.limit {
+		return
+	}
+	if cur.page > 0 {
+		cur.page = (cur.page-1)*cur.limit/limit + 1
+	}
+	cur.limit = limit
+}
+
+// Goto fetches a page and moves the cursor there. a page past the last
+// one is an error once the number of pages is known
+func (cur *Cursor) Goto(ctx context.Context, page int) (ResourceList, error) {
+	if page < 1 || (cur.page > 0 && page > cur.Pages()) {
+		return ResourceList{}, cur.outOfRange(page)
+	}
+	url := fmt.Sprintf("%s?offset=%d&limit=%d", cur.client.URL(cur.resource), (page-1)*cur.limit, cur.limit)
+	var list ResourceList
+	err := cur.client.GetJSON(ctx, url, &list)
+	if err != nil {
+		return ResourceList{}, err
+	}
+	if len(list.Results) == 0 && page > 1 {
+		cur.count = list.Count
+		return ResourceList{}, cur.outOfRange(page)
+	}
+	cur.page, cur.count = page, list.Count
+	return list, nil
+}
+
+// Current fetches the page the cursor is on again, the first page to begin with
+func (cur *Cursor) Current(ctx context.Context) (ResourceList, error) {
+	return cur.Goto(ctx, max(1, cur.page))
+}
+
+func (cur *Cursor) Next(ctx context.Context) (ResourceList, error) {
+	if cur.page > 0 && cur.page >= cur.Pages() {
+		return ResourceList{}, ErrLastPage
+	}
+	return cur.Goto(ctx, cur.page+1)
+}
+
+func (cur *Cursor) Prev(ctx context.Context) (ResourceList, error) {
+	if cur.page <= 1 {
+		return ResourceList{}, ErrFirstPage
+	}
+	return cur.Goto(ctx, cur.page-1)
+}
+
+// Last fetches the last page, which needs the first one when the number
+// of pages isn't known yet
+func (cur *Cursor) Last(ctx context.Context) (ResourceList, error) {
+	if cur.page == 0 {
+		_, err := cur.Goto(ctx, 1)
+		if err != nil {
+			return ResourceList{}, err
+		}
+	}
+	return cur.Goto(ctx, cur.Pages())
+}
+
+func (cur *Cursor) outOfRange(page int) error {
+	if cur.count == 0 {
+		return fmt.Errorf("there is no page %d of %s", page, cur.resource)
+	}
+	pages := max(1, (cur.count+cur.limit-1)/cur.limit)
+	return fmt.Errorf("there is no page %d of %s, pages go from 1 to %d", page, cur.resource, pages)
+}
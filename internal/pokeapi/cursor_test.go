@@ -0,0 +1,68 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi/pokeapitest"
+	"github.com/srijan-raghavula/pokedex/internal/pokecache"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	server := pokeapitest.NewServer(t)
+	client := NewClient(server.BaseURL, time.Second, pokecache.NewCache(time.Minute))
+	ctx := context.Background()
+
+	// 18 types, 5 to a page
+	cur := client.Cursor("type", 5)
+	if cur.Pages() != 0 {
+		t.Errorf("expected no pages before the first fetch, got %d", cur.Pages())
+	}
+	_, err := cur.Prev(ctx)
+	if !errors.Is(err, ErrFirstPage) {
+		t.Errorf("expected ErrFirstPage, got %v", err)
+	}
+	list, err := cur.Next(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur.Page() != 1 || cur.Pages() != 4 || cur.Count() != 18 || list.Results[0].Name != "normal" {
+		t.Errorf("unexpected first page %d/%d: %+v", cur.Page(), cur.Pages(), list.Results)
+	}
+
+	list, err = cur.Last(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur.Page() != 4 || len(list.Results) != 3 {
+		t.Errorf("expected 3 types on the last page, got %d on page %d", len(list.Results), cur.Page())
+	}
+	_, err = cur.Next(ctx)
+	if !errors.Is(err, ErrLastPage) || cur.Page() != 4 {
+		t.Errorf("expected ErrLastPage on page 4, got %v on page %d", err, cur.Page())
+	}
+	_, err = cur.Goto(ctx, 5)
+	if err == nil || err.Error() != "there is no page 5 of type, pages go from 1 to 4" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	list, err = cur.Prev(ctx)
+	if err != nil || cur.Page() != 3 || list.Results[0].Name != "psychic" {
+		t.Errorf("unexpected previous page %d: %+v %v", cur.Page(), list.Results, err)
+	}
+
+	// psychic is the 11th type, so the 11th page of one type each
+	cur.SetLimit(1)
+	list, err = cur.Current(ctx)
+	if err != nil || cur.Page() != 11 || cur.Pages() != 18 || list.Results[0].Name != "psychic" {
+		t.Errorf("unexpected page %d/%d after changing the limit: %+v %v", cur.Page(), cur.Pages(), list.Results, err)
+	}
+
+	// the page count is found out when going past the end first thing
+	cur = client.Cursor("type", 10)
+	_, err = cur.Goto(ctx, 3)
+	if err == nil || err.Error() != "there is no page 3 of type, pages go from 1 to 2" || cur.Page() != 0 {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
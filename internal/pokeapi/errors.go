@@ -0,0 +1,41 @@
+package pokeapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrRateLimited = errors.New("rate limited by PokeAPI")
+	ErrServer      = errors.New("PokeAPI server error")
+)
+
+type StatusError struct {
+	URL        string
+	StatusCode int
+	// a plain text explanation that came with the status, like the
+	// resource an offline snapshot is missing
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s (response status code: %d)", e.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("response status code: %d (%s)", e.StatusCode, e.URL)
+}
+
+// lets callers match a StatusError with errors.Is(err, ErrNotFound) and friends
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package pokeapi
+
+import (
+	"context"
+)
+
+type Item struct {
+	ID            int             `json:"id"`
+	Name          string          `json:"name"`
+	Cost          int             `json:"cost"`
+	Category      NamedResource   `json:"category"`
+	Attributes    []NamedResource `json:"attributes"`
+	EffectEntries []struct {
+		Effect      string        `json:"effect"`
+		ShortEffect string        `json:"short_effect"`
+		Language    NamedResource `json:"language"`
+	} `json:"effect_entries"`
+}
+
+// ShortEffect is the english description of what the item does
+func (i Item) ShortEffect() string {
+	for _, entry := range i.EffectEntries {
+		if entry.Language.Name == "en" {
+			return entry.ShortEffect
+		}
+	}
+	return ""
+}
+
+func (c *Client) Item(ctx context.Context, name string) (Item, error) {
+	var item Item
+	err := c.GetJSON(ctx, c.URL("item", name), &item)
+	return item, err
+}
@@ -0,0 +1,43 @@
+package pokeapi
+
+import (
+	"context"
+)
+
+type LocationArea struct {
+	EncounterMethodRates []struct {
+		EncounterMethod NamedResource `json:"encounter_method"`
+		VersionDetails  []struct {
+			Rate    int           `json:"rate"`
+			Version NamedResource `json:"version"`
+		} `json:"version_details"`
+	} `json:"encounter_method_rates"`
+	GameIndex int           `json:"game_index"`
+	ID        int           `json:"id"`
+	Location  NamedResource `json:"location"`
+	Name      string        `json:"name"`
+	Names     []struct {
+		Language NamedResource `json:"language"`
+		Name     string        `json:"name"`
+	} `json:"names"`
+	PokemonEncounters []struct {
+		Pokemon        NamedResource `json:"pokemon"`
+		VersionDetails []struct {
+			EncounterDetails []struct {
+				Chance          int           `json:"chance"`
+				ConditionValues []any         `json:"condition_values"`
+				MaxLevel        int           `json:"max_level"`
+				Method          NamedResource `json:"method"`
+				MinLevel        int           `json:"min_level"`
+			} `json:"encounter_details"`
+			MaxChance int           `json:"max_chance"`
+			Version   NamedResource `json:"version"`
+		} `json:"version_details"`
+	} `json:"pokemon_encounters"`
+}
+
+func (c *Client) LocationArea(ctx context.Context, name string) (LocationArea, error) {
+	var area LocationArea
+	err := c.GetJSON(ctx, c.URL("location-area", name), &area)
+	return area, err
+}
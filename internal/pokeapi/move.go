@@ -0,0 +1,22 @@
+package pokeapi
+
+import (
+	"context"
+)
+
+type Move struct {
+	ID          int           `json:"id"`
+	Name        string        `json:"name"`
+	Accuracy    int           `json:"accuracy"`
+	Power       int           `json:"power"`
+	PP          int           `json:"pp"`
+	Priority    int           `json:"priority"`
+	DamageClass NamedResource `json:"damage_class"`
+	Type        NamedResource `json:"type"`
+}
+
+func (c *Client) Move(ctx context.Context, name string) (Move, error) {
+	var move Move
+	err := c.GetJSON(ctx, c.URL("move", name), &move)
+	return move, err
+}
@@ -0,0 +1,13 @@
+package pokeapi
+
+type NamedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type ResourceList struct {
+	Count    int             `json:"count"`
+	Next     string          `json:"next"`
+	Previous string          `json:"previous"`
+	Results  []NamedResource `json:"results"`
+}
@@ -0,0 +1,100 @@
+package pokeapi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type RetryPolicy struct {
+	// attempts including the first one, 1 disables retries
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// deadline for a whole request, all attempts and waits included
+	Deadline time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Millisecond * 250,
+	MaxDelay:    time.Second * 5,
+	Deadline:    time.Second * 30,
+}
+
+// 429s, 5xxs and network errors are worth another try, anything else
+// (404s, a cancelled command, a resource missing from a snapshot) is not
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServer) {
+		return true
+	}
+	if errors.Is(err, ErrNotFound) {
+		return false
+	}
+	// a url.Error is a net.Error whatever went wrong, only what it wraps
+	// tells whether the network was at fault or a transport refused
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// full jitter exponential backoff, unless the server told us how long to wait
+func (p RetryPolicy) delay(attempt int, header http.Header) time.Duration {
+	if wait, ok := retryAfter(header); ok {
+		return wait
+	}
+	backoff := p.BaseDelay << attempt
+	if backoff > p.MaxDelay || backoff <= 0 {
+		backoff = p.MaxDelay
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// Retry-After is either a number of seconds or an http date
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Second * time.Duration(seconds), true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	wait := time.Until(date)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
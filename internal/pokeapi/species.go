@@ -0,0 +1,92 @@
+package pokeapi
+
+import (
+	"context"
+)
+
+type Species struct {
+	ID                 int            `json:"id"`
+	Name               string         `json:"name"`
+	CaptureRate        int            `json:"capture_rate"`
+	BaseHappiness      int            `json:"base_happiness"`
+	IsBaby             bool           `json:"is_baby"`
+	IsLegendary        bool           `json:"is_legendary"`
+	IsMythical         bool           `json:"is_mythical"`
+	EvolvesFromSpecies *NamedResource `json:"evolves_from_species"`
+	EvolutionChain     struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	Varieties []struct {
+		IsDefault bool          `json:"is_default"`
+		Pokemon   NamedResource `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+// DefaultPokemon is the name of the pokemon that stands for the species
+func (s Species) DefaultPokemon() string {
+	for _, variety := range s.Varieties {
+		if variety.IsDefault {
+			return variety.Pokemon.Name
+		}
+	}
+	return s.Name
+}
+
+func (c *Client) Species(ctx context.Context, name string) (Species, error) {
+	var species Species
+	err := c.GetJSON(ctx, c.URL("pokemon-species", name), &species)
+	return species, err
+}
+
+type EvolutionDetail struct {
+	Trigger               NamedResource  `json:"trigger"`
+	MinLevel              *int           `json:"min_level"`
+	Item                  *NamedResource `json:"item"`
+	HeldItem              *NamedResource `json:"held_item"`
+	KnownMove             *NamedResource `json:"known_move"`
+	KnownMoveType         *NamedResource `json:"known_move_type"`
+	Location              *NamedResource `json:"location"`
+	PartySpecies          *NamedResource `json:"party_species"`
+	PartyType             *NamedResource `json:"party_type"`
+	TradeSpecies          *NamedResource `json:"trade_species"`
+	Gender                *int           `json:"gender"`
+	MinHappiness          *int           `json:"min_happiness"`
+	MinBeauty             *int           `json:"min_beauty"`
+	MinAffection          *int           `json:"min_affection"`
+	RelativePhysicalStats *int           `json:"relative_physical_stats"`
+	TimeOfDay             string         `json:"time_of_day"`
+	NeedsOverworldRain    bool           `json:"needs_overworld_rain"`
+	TurnUpsideDown        bool           `json:"turn_upside_down"`
+}
+
+type ChainLink struct {
+	IsBaby           bool              `json:"is_baby"`
+	Species          NamedResource     `json:"species"`
+	EvolutionDetails []EvolutionDetail `json:"evolution_details"`
+	EvolvesTo        []ChainLink       `json:"evolves_to"`
+}
+
+// Find returns the link for a species somewhere down the chain
+func (l ChainLink) Find(species string) (ChainLink, bool) {
+	if l.Species.Name == species {
+		return l, true
+	}
+	for _, next := range l.EvolvesTo {
+		if found, ok := next.Find(species); ok {
+			return found, true
+		}
+	}
+	return ChainLink{}, false
+}
+
+type EvolutionChain struct {
+	ID    int       `json:"id"`
+	Chain ChainLink `json:"chain"`
+}
+
+// EvolutionChain takes the url from Species.EvolutionChain, chains have no names
+func (c *Client) EvolutionChain(ctx context.Context, url string) (EvolutionChain, error) {
+	var chain EvolutionChain
+	err := c.GetJSON(ctx, url, &chain)
+	return chain, err
+}
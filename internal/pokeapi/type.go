@@ -0,0 +1,26 @@
+package pokeapi
+
+import (
+	"context"
+)
+
+type DamageRelations struct {
+	DoubleDamageFrom []NamedResource `json:"double_damage_from"`
+	DoubleDamageTo   []NamedResource `json:"double_damage_to"`
+	HalfDamageFrom   []NamedResource `json:"half_damage_from"`
+	HalfDamageTo     []NamedResource `json:"half_damage_to"`
+	NoDamageFrom     []NamedResource `json:"no_damage_from"`
+	NoDamageTo       []NamedResource `json:"no_damage_to"`
+}
+
+type Type struct {
+	ID              int             `json:"id"`
+	Name            string          `json:"name"`
+	DamageRelations DamageRelations `json:"damage_relations"`
+}
+
+func (c *Client) Type(ctx context.Context, name string) (Type, error) {
+	var t Type
+	err := c.GetJSON(ctx, c.URL("type", name), &t)
+	return t, err
+}
@@ -0,0 +1,57 @@
+package pokeapi
+
+import (
+	"context"
+	"strings"
+)
+
+type Version struct {
+	ID           int           `json:"id"`
+	Name         string        `json:"name"`
+	VersionGroup NamedResource `json:"version_group"`
+}
+
+func (c *Client) Version(ctx context.Context, name string) (Version, error) {
+	var version Version
+	err := c.GetJSON(ctx, c.URL("version", name), &version)
+	return version, err
+}
+
+// VersionGroup is the games that share their data, like diamond and pearl
+type VersionGroup struct {
+	ID         int             `json:"id"`
+	Name       string          `json:"name"`
+	Order      int             `json:"order"`
+	Generation NamedResource   `json:"generation"`
+	Versions   []NamedResource `json:"versions"`
+}
+
+func (c *Client) VersionGroup(ctx context.Context, name string) (VersionGroup, error) {
+	var group VersionGroup
+	err := c.GetJSON(ctx, c.URL("version-group", name), &group)
+	return group, err
+}
+
+var numerals = map[byte]int{'i': 1, 'v': 5, 'x': 10}
+
+// GenerationNumber turns a generation name like "generation-iv" into 4, it
+// returns 0 for anything else
+func GenerationNumber(name string) int {
+	roman, ok := strings.CutPrefix(name, "generation-")
+	if !ok || roman == "" {
+		return 0
+	}
+	n := 0
+	for i := 0; i < len(roman); i++ {
+		value, ok := numerals[roman[i]]
+		if !ok {
+			return 0
+		}
+		if i+1 < len(roman) && numerals[roman[i+1]] > value {
+			n -= value
+		} else {
+			n += value
+		}
+	}
+	return n
+}
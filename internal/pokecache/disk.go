@@ -0,0 +1,169 @@
+package pokecache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+type DiskCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+	mu       *sync.Mutex
+	// bytes on disk, kept as entries are added so the directory is only
+	// read again when it goes over maxBytes. -1 until it's first read
+	size int64
+	// how many times the directory was read, for tests
+	scans int
+}
+
+// eviction goes down to this share of maxBytes, so a full cache isn't
+// read again on every write
+const lowWater = 0.9
+
+func DefaultDiskDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pokedex", "http"), nil
+}
+
+func NewDiskCache(dir string, ttl time.Duration, maxBytes int64) (*DiskCache, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	return &DiskCache{
+		dir:      dir,
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		mu:       &sync.Mutex{},
+		size:     -1,
+	}, nil
+}
+
+func (d *DiskCache) Add(key string, val []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.size < 0 {
+		err := d.evict()
+		if err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(d.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(val)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	path := d.path(key)
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return err
+	}
+	d.size += int64(len(val)) - replaced
+	if d.maxBytes > 0 && d.size > d.maxBytes {
+		return d.evict()
+	}
+	return nil
+}
+
+func (d *DiskCache) Get(key string) ([]byte, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	path := d.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+	if d.expired(info) {
+		if os.Remove(path) == nil && d.size >= 0 {
+			d.size -= info.Size()
+		}
+		return nil, false
+	}
+	val, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
+
+// removes expired entries, then the oldest ones until the cache is down to
+// lowWater of maxBytes, and counts the bytes that are left
+func (d *DiskCache) evict() error {
+	d.scans++
+	dirEntries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return err
+	}
+
+	var files []os.FileInfo
+	var total int64
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		if d.expired(info) {
+			os.Remove(filepath.Join(d.dir, info.Name()))
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+
+	d.size = total
+	if d.maxBytes <= 0 || total <= d.maxBytes {
+		return nil
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	target := int64(float64(d.maxBytes) * lowWater)
+	for _, info := range files {
+		if total <= target {
+			break
+		}
+		err := os.Remove(filepath.Join(d.dir, info.Name()))
+		if err != nil {
+			return err
+		}
+		total -= info.Size()
+		d.size = total
+	}
+	return nil
+}
+
+func (d *DiskCache) expired(info os.FileInfo) bool {
+	return d.ttl > 0 && time.Since(info.ModTime()) > d.ttl
+}
+
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:]))
+}
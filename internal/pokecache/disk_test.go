@@ -0,0 +1,124 @@
+package pokecache
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDiskFallthrough(t *testing.T) {
+	const interval = time.Millisecond * 5
+	disk, err := NewDiskCache(t.TempDir(), time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := NewCacheWithDisk(interval, disk)
+	cache.Add("test.com", []byte("data"))
+
+	time.Sleep(interval * 3)
+
+	val, ok := cache.Get("test.com")
+	if !ok {
+		t.Errorf("expected the disk tier to serve the reaped key")
+		return
+	}
+	if string(val) != "data" {
+		t.Errorf("values not equal")
+	}
+}
+
+func TestDiskTTL(t *testing.T) {
+	const ttl = time.Millisecond * 5
+	disk, err := NewDiskCache(t.TempDir(), ttl, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = disk.Add("test.com", []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(ttl * 2)
+
+	_, ok := disk.Get("test.com")
+	if ok {
+		t.Errorf("expired key served")
+	}
+}
+
+func TestDiskEviction(t *testing.T) {
+	dir := t.TempDir()
+	disk, err := NewDiskCache(dir, time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = disk.Add("old.com", []byte("123456"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// make sure the first entry is strictly older
+	old := time.Now().Add(-time.Second)
+	os.Chtimes(disk.path("old.com"), old, old)
+	err = disk.Add("new.com", []byte("123456"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ok := disk.Get("old.com")
+	if ok {
+		t.Errorf("oldest key not evicted")
+	}
+	_, ok = disk.Get("new.com")
+	if !ok {
+		t.Errorf("newest key evicted")
+	}
+}
+
+func TestDiskReplace(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = disk.Add("keep.com", []byte("1234"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// writing a key again replaces its bytes instead of adding to them
+	for i := 0; i < 3; i++ {
+		err = disk.Add("again.com", []byte("123456"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if disk.size != 10 {
+		t.Errorf("expected 10 bytes on disk, counted %d", disk.size)
+	}
+	for _, key := range []string{"keep.com", "again.com"} {
+		if _, ok := disk.Get(key); !ok {
+			t.Errorf("%s evicted while the cache fits", key)
+		}
+	}
+}
+
+func TestDiskEvictionHeadroom(t *testing.T) {
+	disk, err := NewDiskCache(t.TempDir(), time.Minute, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const adds = 200
+	for i := 0; i < adds; i++ {
+		err := disk.Add(fmt.Sprintf("%d.com", i), []byte("0123456789"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if disk.size > 1000 {
+		t.Errorf("expected the cache to fit in 1000 bytes, it has %d", disk.size)
+	}
+	// past the cap each scan makes room for the next ten entries
+	if disk.scans > adds/5 {
+		t.Errorf("expected the directory to be read at most %d times, read %d", adds/5, disk.scans)
+	}
+}
@@ -8,6 +8,7 @@ import (
 type Cache struct {
 	entries map[string]cacheEntry
 	mu      *sync.Mutex
+	disk    *DiskCache
 }
 
 type cacheEntry struct {
@@ -24,32 +25,56 @@ func NewCache(interval time.Duration) Cache {
 	return cache
 }
 
+// entries reaped from memory are still served from disk until the disk ttl runs out
+func NewCacheWithDisk(interval time.Duration, disk *DiskCache) Cache {
+	cache := NewCache(interval)
+	cache.disk = disk
+	return cache
+}
+
 func (c *Cache) Add(key string, val []byte) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	c.entries[key] = cacheEntry{
 		val:       val,
 		createdAt: time.Now().UTC(),
 	}
+	c.mu.Unlock()
+	// the disk has a lock of its own, lookups in memory don't wait on it
+	if c.disk != nil {
+		// the disk tier is best effort, a failed write only costs a refetch later
+		c.disk.Add(key, val)
+	}
 }
 
 func (c *Cache) Get(key string) ([]byte, bool) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
 	entry, ok := c.entries[key]
-	return entry.val, ok
+	c.mu.Unlock()
+	if ok || c.disk == nil {
+		return entry.val, ok
+	}
+	val, ok := c.disk.Get(key)
+	if ok {
+		c.mu.Lock()
+		c.entries[key] = cacheEntry{
+			val:       val,
+			createdAt: time.Now().UTC(),
+		}
+		c.mu.Unlock()
+	}
+	return val, ok
 }
 
 func (c *Cache) reapLoop(interval time.Duration) {
 	tick := time.NewTicker(interval)
 	for range tick.C {
 		c.mu.Lock()
-		c.mu.Unlock()
 		toDelBefore := time.Now().UTC().Add(-interval)
 		for k, v := range c.entries {
 			if v.createdAt.Before(toDelBefore) {
 				delete(c.entries, k)
 			}
 		}
+		c.mu.Unlock()
 	}
 }
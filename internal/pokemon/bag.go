@@ -0,0 +1,128 @@
+package pokemon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// what a new game starts with, and what saves from before there was
+// a bag are given
+var StarterBag = map[string]int{
+	"poke-ball":  10,
+	"great-ball": 3,
+	"potion":     3,
+	"oran-berry": 2,
+}
+
+func starterBag() map[string]int {
+	bag := make(map[string]int, len(StarterBag))
+	for name, count := range StarterBag {
+		bag[name] = count
+	}
+	return bag
+}
+
+type BagItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Items returns what's in the bag sorted by name
+func (c *Pokedex) Items() []BagItem {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	items := make([]BagItem, 0, len(c.Bag))
+	for name, count := range c.Bag {
+		items = append(items, BagItem{Name: name, Count: count})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+func (c *Pokedex) Count(item string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Bag[item]
+}
+
+func (c *Pokedex) AddItem(item string, n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Bag == nil {
+		c.Bag = make(map[string]int)
+	}
+	c.Bag[item] += n
+}
+
+// UseItem takes one of an item out of the bag
+func (c *Pokedex) UseItem(item string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Bag[item] <= 0 {
+		return fmt.Errorf("You don't have any %s in your bag", item)
+	}
+	c.Bag[item]--
+	if c.Bag[item] == 0 {
+		delete(c.Bag, item)
+	}
+	return nil
+}
+
+// Heal is what a potion, berry or revive does. HP is a fixed amount,
+// Fraction a share of the pokemon's max HP
+type Heal struct {
+	HP       int
+	Fraction float64
+	// revives only work on fainted pokemon, and nothing else does
+	Revive bool
+}
+
+var Heals = map[string]Heal{
+	"potion":       {HP: 20},
+	"super-potion": {HP: 50},
+	"hyper-potion": {HP: 200},
+	"max-potion":   {Fraction: 1},
+	"full-restore": {Fraction: 1},
+	"fresh-water":  {HP: 50},
+	"oran-berry":   {HP: 10},
+	"sitrus-berry": {Fraction: 0.25},
+	"revive":       {Fraction: 0.5, Revive: true},
+	"max-revive":   {Fraction: 1, Revive: true},
+}
+
+// Apply returns the damage a pokemon is left with after healing
+func (h Heal) Apply(p Caught, maxHP int) (int, error) {
+	fainted := p.Damage >= maxHP
+	switch {
+	case fainted && !h.Revive:
+		return 0, fmt.Errorf("%s has fainted, it needs a revive", p.Name())
+	case !fainted && h.Revive:
+		return 0, fmt.Errorf("%s hasn't fainted", p.Name())
+	case p.Damage == 0:
+		return 0, fmt.Errorf("%s is already at full HP", p.Name())
+	}
+	healed := h.HP + int(h.Fraction*float64(maxHP))
+	damage := p.Damage
+	if fainted {
+		damage = maxHP
+	}
+	return max(damage-healed, 0), nil
+}
+
+// SetDamage records how much HP a pokemon has lost
+func (c *Pokedex) SetDamage(id, damage int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := c.index(id)
+	if i < 0 {
+		return fmt.Errorf("You don't have a pokemon with ID #%d", id)
+	}
+	if damage < 0 {
+		return errors.New("damage can't be negative")
+	}
+	c.Caught[i].Damage = damage
+	return nil
+}
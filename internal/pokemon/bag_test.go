@@ -0,0 +1,51 @@
+package pokemon
+
+import (
+	"testing"
+)
+
+func TestHeal(t *testing.T) {
+	cases := []struct {
+		item    string
+		damage  int
+		want    int
+		wantErr string
+	}{
+		{"potion", 30, 10, ""},
+		{"potion", 5, 0, ""},
+		{"sitrus-berry", 30, 20, ""},
+		{"max-potion", 39, 0, ""},
+		{"potion", 0, 0, "pikachu is already at full HP"},
+		{"potion", 40, 0, "pikachu has fainted, it needs a revive"},
+		{"revive", 40, 20, ""},
+		{"revive", 10, 0, "pikachu hasn't fainted"},
+	}
+	for _, c := range cases {
+		got, err := Heals[c.item].Apply(Caught{Species: "pikachu", Damage: c.damage}, 40)
+		if c.wantErr != "" {
+			if err == nil || err.Error() != c.wantErr {
+				t.Errorf("%s at %d damage: expected error %q, got %v", c.item, c.damage, c.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got != c.want {
+			t.Errorf("%s at %d damage: expected %d damage left, got %d (%v)", c.item, c.damage, c.want, got, err)
+		}
+	}
+}
+
+func TestUseItem(t *testing.T) {
+	dex := newTestPokedex()
+	dex.AddItem("potion", 1)
+	err := dex.UseItem("potion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dex.UseItem("potion")
+	if err == nil {
+		t.Error("used a potion that wasn't there")
+	}
+	if len(dex.Items()) != 0 {
+		t.Errorf("expected an empty bag, got %v", dex.Items())
+	}
+}
@@ -0,0 +1,179 @@
+package pokemon
+
+import (
+	"context"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// ball modifiers from generations III and IV. the master ball never fails
+var Balls = map[string]float64{
+	"poke-ball":    1,
+	"great-ball":   1.5,
+	"ultra-ball":   2,
+	"safari-ball":  1.5,
+	"premier-ball": 1,
+	"master-ball":  math.Inf(1),
+}
+
+const DefaultBall = "poke-ball"
+
+// status modifiers, sleeping or frozen pokemon are the easiest to catch
+var Statuses = map[string]float64{
+	"":          1,
+	"sleep":     2,
+	"freeze":    2,
+	"paralysis": 1.5,
+	"poison":    1.5,
+	"burn":      1.5,
+}
+
+// Attempt is everything known about a throw when working out the odds
+type Attempt struct {
+	Pokemon     PokemonEndpoint
+	CaptureRate int
+	Ball        string
+	Status      string
+	// how much HP the pokemon has left, from just above 0 to 1. 0 counts
+	// as full health, which every throw from Catch is at
+	HP float64
+}
+
+// CatchStrategy works out the chance of a throw catching a pokemon
+type CatchStrategy interface {
+	Probability(a Attempt) float64
+}
+
+// CaptureFormula is the capture formula from generations III and IV
+type CaptureFormula struct{}
+
+func (CaptureFormula) Probability(a Attempt) float64 {
+	ball, status := Balls[a.Ball], Statuses[a.Status]
+	if math.IsInf(ball, 1) {
+		return 1
+	}
+	hp := a.HP
+	if hp <= 0 || hp > 1 {
+		hp = 1
+	}
+	modified := (3 - 2*hp) / 3 * float64(a.CaptureRate) * ball * status
+	if modified >= 255 {
+		return 1
+	}
+	modified = max(modified, 1)
+	// the games do four shake checks, each passing with b/65535
+	b := 1048560 / math.Sqrt(math.Sqrt(16711680/modified))
+	return math.Pow(b/65535, 4)
+}
+
+// BaseExperienceStrategy is how catching used to work, pokemon that give
+// more experience are harder to catch and nothing else matters
+type BaseExperienceStrategy struct{}
+
+func (BaseExperienceStrategy) Probability(a Attempt) float64 {
+	// max BaseExperience so far is 635 for Blissey
+	return max(701-float64(a.Pokemon.BaseExperience), 0) / 701
+}
+
+// Throw is how a ball is thrown, the zero value is a poke-ball. wild
+// pokemon can't be hurt before they're caught, so HP isn't modelled and
+// every throw is at a pokemon with full health
+type Throw struct {
+	Ball   string
+	Status string
+	// where and at what level the pokemon was found
+	Level    int
+	Location string
+	Nickname string
+}
+
+type Outcome struct {
+	Pokemon     PokemonEndpoint
+	Ball        string
+	Probability float64
+	Caught      bool
+	// only set when the pokemon was caught
+	Instance Caught
+}
+
+type Catcher struct {
+	Strategy CatchStrategy
+	rng      *rand.Rand
+}
+
+// NewCatcher takes the random source so tests can make every throw the same
+func NewCatcher(strategy CatchStrategy, src rand.Source) *Catcher {
+	return &Catcher{Strategy: strategy, rng: rand.New(src)}
+}
+
+func CheckBall(ball string) error {
+	if _, ok := Balls[ball]; !ok {
+		return fmt.Errorf("unknown ball %q (use %s)", ball, names(Balls))
+	}
+	return nil
+}
+
+func CheckStatus(status string) error {
+	if _, ok := Statuses[status]; !ok {
+		return fmt.Errorf("unknown status %q (use %s)", status, names(Statuses))
+	}
+	return nil
+}
+
+// Catch throws a ball from the bag at a pokemon and adds it to Pokemons if
+// it's caught
+func (c *Catcher) Catch(ctx context.Context, client *pokeapi.Client, name string, throw Throw) (Outcome, error) {
+	if throw.Ball == "" {
+		throw.Ball = DefaultBall
+	}
+	err := CheckBall(throw.Ball)
+	if err != nil {
+		return Outcome{}, err
+	}
+	err = CheckStatus(throw.Status)
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	p, err := pokemonInfo(ctx, client, name)
+	if err != nil {
+		return Outcome{}, err
+	}
+	species, err := client.Species(ctx, p.Species.Name)
+	if err != nil {
+		return Outcome{}, err
+	}
+	// the ball is used up whether it catches anything or not
+	err = Pokemons.UseItem(throw.Ball)
+	if err != nil {
+		return Outcome{}, err
+	}
+
+	outcome := Outcome{Pokemon: p, Ball: throw.Ball}
+	outcome.Probability = c.Strategy.Probability(Attempt{
+		Pokemon:     p,
+		CaptureRate: species.CaptureRate,
+		Ball:        throw.Ball,
+		Status:      throw.Status,
+	})
+	outcome.Caught = c.rng.Float64() < outcome.Probability
+	if outcome.Caught {
+		outcome.Instance = Pokemons.Catch(p, throw.Level, throw.Location, throw.Nickname)
+	}
+	return outcome, nil
+}
+
+func names(m map[string]float64) string {
+	var list []string
+	for name := range m {
+		if name != "" {
+			list = append(list, name)
+		}
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
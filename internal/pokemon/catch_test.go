@@ -0,0 +1,35 @@
+package pokemon
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCaptureFormula(t *testing.T) {
+	cases := []struct {
+		name    string
+		attempt Attempt
+		want    float64
+	}{
+		{"magikarp at full hp", Attempt{CaptureRate: 255, Ball: "poke-ball"}, 0.333},
+		{"gyarados at full hp", Attempt{CaptureRate: 45, Ball: "poke-ball"}, 0.0588},
+		{"gyarados in an ultra-ball", Attempt{CaptureRate: 45, Ball: "ultra-ball"}, 0.1176},
+		{"sleeping gyarados", Attempt{CaptureRate: 45, Ball: "poke-ball", Status: "sleep"}, 0.1176},
+		{"gyarados with no hp left", Attempt{CaptureRate: 45, Ball: "poke-ball", HP: 0.01}, 0.1753},
+		{"magikarp in a great-ball at low hp", Attempt{CaptureRate: 255, Ball: "great-ball", HP: 0.1}, 1},
+		{"master-ball", Attempt{CaptureRate: 3, Ball: "master-ball"}, 1},
+	}
+	for _, c := range cases {
+		got := CaptureFormula{}.Probability(c.attempt)
+		if math.Abs(got-c.want) > 0.001 {
+			t.Errorf("%s: expected %.4f, got %.4f", c.name, c.want, got)
+		}
+	}
+}
+
+func TestBaseExperienceStrategy(t *testing.T) {
+	got := BaseExperienceStrategy{}.Probability(Attempt{Pokemon: PokemonEndpoint{BaseExperience: 40}})
+	if math.Abs(got-661.0/701) > 1e-9 {
+		t.Errorf("expected magikarp to be caught %.4f of the time, got %.4f", 661.0/701, got)
+	}
+}
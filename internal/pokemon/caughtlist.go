@@ -3,17 +3,46 @@ package pokemon
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 )
 
+// Pokedex is the registry of species you have caught, with every pokemon
+// you caught and your party alongside it
 type Pokedex struct {
 	mu   *sync.Mutex
 	List map[string]PokemonEndpoint
+	// in the order they were caught
+	Caught []Caught
+	// IDs of the pokemon in your party, in slot order
+	Party []int
+	// the ID of the last pokemon caught
+	NextID int
+	// item name to how many you have
+	Bag map[string]int
 }
 
 var Pokemons = Pokedex{
 	mu:   &sync.Mutex{},
 	List: make(map[string]PokemonEndpoint),
+	Bag:  starterBag(),
+}
+
+// pokemon are caught at this level and grow one level per battle won
+const (
+	CatchLevel = 5
+	MaxLevel   = 100
+)
+
+// Reset empties the Pokedex
+func (c *Pokedex) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.List = make(map[string]PokemonEndpoint)
+	c.Caught = nil
+	c.Party = nil
+	c.NextID = 0
+	c.Bag = starterBag()
 }
 
 func (c *Pokedex) Add(name string, pokemon PokemonEndpoint) {
@@ -32,15 +61,27 @@ func (c *Pokedex) Get(name string) (PokemonEndpoint, error) {
 	return pokemon, nil
 }
 
-func (c *Pokedex) Print() error {
+func (c *Pokedex) Names() []string {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if len(c.List) == 0 {
-		return errors.New("You haven't caught any Pokemons...YET!")
-	}
-	fmt.Println("==Your Pokedex==")
+	names := make([]string, 0, len(c.List))
 	for k := range c.List {
-		fmt.Println(k)
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// All returns every pokemon in the Pokedex sorted by name
+func (c *Pokedex) All() []PokemonEndpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	all := make([]PokemonEndpoint, 0, len(c.List))
+	for _, pokemon := range c.List {
+		all = append(all, pokemon)
 	}
-	return nil
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+	return all
 }
@@ -0,0 +1,169 @@
+package pokemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"strings"
+)
+
+// EvolutionChain returns the whole chain a pokemon belongs to, from its
+// earliest stage. species is the name from PokemonEndpoint.Species
+func EvolutionChain(ctx context.Context, client *pokeapi.Client, species string) (pokeapi.ChainLink, error) {
+	s, err := client.Species(ctx, species)
+	if err != nil {
+		return pokeapi.ChainLink{}, err
+	}
+	if s.EvolutionChain.URL == "" {
+		return pokeapi.ChainLink{Species: pokeapi.NamedResource{Name: s.Name}}, nil
+	}
+	chain, err := client.EvolutionChain(ctx, s.EvolutionChain.URL)
+	if err != nil {
+		return pokeapi.ChainLink{}, err
+	}
+	return chain.Chain, nil
+}
+
+// Conditions describes what an evolution needs, like "level 20" or "thunder-stone"
+func Conditions(detail pokeapi.EvolutionDetail) []string {
+	var conditions []string
+	add := func(format string, args ...any) {
+		conditions = append(conditions, fmt.Sprintf(format, args...))
+	}
+	if detail.MinLevel != nil {
+		add("level %d", *detail.MinLevel)
+	}
+	if detail.Item != nil {
+		add("%s", detail.Item.Name)
+	}
+	if detail.HeldItem != nil {
+		add("holding %s", detail.HeldItem.Name)
+	}
+	if detail.KnownMove != nil {
+		add("knows %s", detail.KnownMove.Name)
+	}
+	if detail.KnownMoveType != nil {
+		add("knows a %s move", detail.KnownMoveType.Name)
+	}
+	if detail.MinHappiness != nil {
+		add("happiness %d", *detail.MinHappiness)
+	}
+	if detail.MinBeauty != nil {
+		add("beauty %d", *detail.MinBeauty)
+	}
+	if detail.MinAffection != nil {
+		add("affection %d", *detail.MinAffection)
+	}
+	switch detail.TimeOfDay {
+	case "":
+	case "night":
+		add("at night")
+	default:
+		add("during the %s", detail.TimeOfDay)
+	}
+	if detail.Location != nil {
+		add("at %s", detail.Location.Name)
+	}
+	if detail.Gender != nil {
+		// PokeAPI's gender ids
+		if *detail.Gender == 1 {
+			add("female")
+		} else {
+			add("male")
+		}
+	}
+	if detail.PartySpecies != nil {
+		add("with %s in the party", detail.PartySpecies.Name)
+	}
+	if detail.PartyType != nil {
+		add("with a %s type in the party", detail.PartyType.Name)
+	}
+	if detail.TradeSpecies != nil {
+		add("for %s", detail.TradeSpecies.Name)
+	}
+	if detail.RelativePhysicalStats != nil {
+		switch *detail.RelativePhysicalStats {
+		case 1:
+			add("attack > defense")
+		case -1:
+			add("attack < defense")
+		default:
+			add("attack = defense")
+		}
+	}
+	if detail.NeedsOverworldRain {
+		add("while raining")
+	}
+	if detail.TurnUpsideDown {
+		add("upside down")
+	}
+	return conditions
+}
+
+// Describe is the trigger followed by its conditions, like "level-up: level 20"
+func Describe(detail pokeapi.EvolutionDetail) string {
+	conditions := Conditions(detail)
+	if len(conditions) == 0 {
+		return detail.Trigger.Name
+	}
+	return detail.Trigger.Name + ": " + strings.Join(conditions, ", ")
+}
+
+// levels and items are tracked so far, an evolution that needs
+// anything else can't happen. item is the item being used, if any
+func evolutionMet(detail pokeapi.EvolutionDetail, name string, level int, item string) error {
+	untracked := detail
+	untracked.Trigger = pokeapi.NamedResource{}
+	untracked.MinLevel = nil
+	untracked.Item = nil
+	switch {
+	case len(Conditions(untracked)) > 0 || (detail.Trigger.Name != "level-up" && detail.Trigger.Name != "use-item"):
+		return fmt.Errorf("needs %s, which isn't tracked yet", Describe(detail))
+	case detail.Trigger.Name == "use-item" && detail.Item != nil && detail.Item.Name != item:
+		return fmt.Errorf("needs a %s (use %s %s)", detail.Item.Name, detail.Item.Name, name)
+	case detail.Trigger.Name == "level-up" && item != "":
+		return fmt.Errorf("doesn't evolve with a %s", item)
+	case detail.MinLevel != nil && level < *detail.MinLevel:
+		return fmt.Errorf("needs level %d (%s is level %d)", *detail.MinLevel, name, level)
+	}
+	return nil
+}
+
+// NextEvolution finds the first evolution of a caught pokemon whose conditions
+// are met. into picks one when a pokemon can evolve into more than one species,
+// leave it empty to take any of them. item is an item being used on the
+// pokemon, leave it empty for evolutions by level
+func (c *Pokedex) NextEvolution(ctx context.Context, client *pokeapi.Client, p Caught, into, item string) (string, error) {
+	name := p.Name()
+	endpoint, err := c.Get(p.Species)
+	if err != nil {
+		return "", err
+	}
+	chain, err := EvolutionChain(ctx, client, endpoint.Species.Name)
+	if err != nil {
+		return "", err
+	}
+	link, ok := chain.Find(endpoint.Species.Name)
+	if !ok || len(link.EvolvesTo) == 0 {
+		return "", fmt.Errorf("%s doesn't evolve any further", name)
+	}
+
+	var reasons []string
+	for _, next := range link.EvolvesTo {
+		if into != "" && next.Species.Name != into {
+			continue
+		}
+		for _, detail := range next.EvolutionDetails {
+			err := evolutionMet(detail, name, p.Level, item)
+			if err == nil {
+				return next.Species.Name, nil
+			}
+			reasons = append(reasons, fmt.Sprintf("%s %v", next.Species.Name, err))
+		}
+	}
+	if len(reasons) == 0 {
+		return "", fmt.Errorf("%s doesn't evolve into %s", name, into)
+	}
+	return "", errors.New(name + " can't evolve yet: " + strings.Join(reasons, "; "))
+}
@@ -0,0 +1,140 @@
+package pokemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"github.com/srijan-raghavula/pokedex/internal/snapshot"
+	"strings"
+)
+
+// Game is the version of the games lookups are narrowed to. the zero value
+// is every version at once, with the types and sprites pokemon have today
+type Game struct {
+	Version      string `json:"version,omitempty"`
+	VersionGroup string `json:"version_group,omitempty"`
+	Generation   int    `json:"generation,omitempty"`
+}
+
+// NewGame looks a version like "platinum" up along with its version group
+// and generation
+func NewGame(ctx context.Context, client *pokeapi.Client, version string) (Game, error) {
+	v, err := client.Version(ctx, version)
+	if errors.Is(err, pokeapi.ErrNotFound) && !snapshot.IsMissing(err) {
+		return Game{}, fmt.Errorf("unknown version %q (try red, diamond or platinum)", version)
+	}
+	if err != nil {
+		return Game{}, err
+	}
+	group, err := client.VersionGroup(ctx, v.VersionGroup.Name)
+	if err != nil {
+		return Game{}, err
+	}
+	return Game{
+		Version:      v.Name,
+		VersionGroup: group.Name,
+		Generation:   pokeapi.GenerationNumber(group.Generation.Name),
+	}, nil
+}
+
+// All is true for the zero Game
+func (g Game) All() bool {
+	return g.Version == ""
+}
+
+// HasVersionGroup reports whether data for a version group, like a move
+// learnset, belongs to the game
+func (g Game) HasVersionGroup(group string) bool {
+	return g.All() || g.VersionGroup == group
+}
+
+// Types are the types a pokemon had in the game. past_types lists the types
+// a pokemon had up to and including a generation, the earliest generation
+// that's not before the game's wins
+func (g Game) Types(p PokemonEndpoint) []string {
+	var types []string
+	for _, pokemonType := range p.Types {
+		types = append(types, pokemonType.Type.Name)
+	}
+	if g.Generation == 0 {
+		return types
+	}
+	best := 0
+	for _, past := range p.PastTypes {
+		generation := pokeapi.GenerationNumber(past.Generation.Name)
+		if generation < g.Generation || (best != 0 && generation >= best) {
+			continue
+		}
+		best = generation
+		types = types[:0:0]
+		for _, pastType := range past.Types {
+			types = append(types, pastType.Type.Name)
+		}
+	}
+	return types
+}
+
+// Sprite is the front sprite of a pokemon in the game, falling back to
+// today's sprite when the game has none
+func (g Game) Sprite(p PokemonEndpoint) string {
+	if sprite := g.sprite(p, "front_default"); sprite != "" {
+		return sprite
+	}
+	return p.Sprites.FrontDefault
+}
+
+// SpriteVariants are the sprites there are besides the front one
+var SpriteVariants = []string{"shiny", "back", "gen1"}
+
+// SpriteVariant is one of SpriteVariants, or the front sprite for "front".
+// gen1 is the sprite of red and blue whatever the game
+func (g Game) SpriteVariant(p PokemonEndpoint, variant string) (string, error) {
+	sprite := ""
+	switch variant {
+	case "front":
+		sprite = g.Sprite(p)
+	case "shiny":
+		sprite = g.sprite(p, "front_shiny")
+		if sprite == "" {
+			sprite = p.Sprites.FrontShiny
+		}
+	case "back":
+		sprite = g.sprite(p, "back_default")
+		if sprite == "" {
+			sprite = p.Sprites.BackDefault
+		}
+	case "gen1":
+		sprite = Game{Version: "red", VersionGroup: "red-blue", Generation: 1}.sprite(p, "front_default")
+	default:
+		return "", fmt.Errorf("unknown sprite %q (use front, %s)", variant, strings.Join(SpriteVariants, ", "))
+	}
+	if sprite == "" {
+		return "", fmt.Errorf("%s has no %s sprite", p.Name, variant)
+	}
+	return sprite, nil
+}
+
+// sprite is a sprite of the game, like front_default, or "" when the
+// game doesn't have it
+func (g Game) sprite(p PokemonEndpoint, key string) string {
+	if g.All() {
+		return ""
+	}
+	// versions is keyed by generation, then by version group, or by
+	// version for the games of generation II
+	var generations map[string]map[string]map[string]any
+	body, err := json.Marshal(p.Sprites.Versions)
+	if err != nil || json.Unmarshal(body, &generations) != nil {
+		return ""
+	}
+	for _, games := range generations {
+		for _, name := range []string{g.Version, g.VersionGroup} {
+			if sprite, ok := games[name][key].(string); ok && sprite != "" {
+				return sprite
+			}
+		}
+	}
+	return ""
+}
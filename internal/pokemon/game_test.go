@@ -0,0 +1,74 @@
+package pokemon
+
+import (
+	"encoding/json"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"slices"
+	"testing"
+)
+
+// clefairy was normal until fairy arrived in generation VI
+const clefairy = `{"name":"clefairy",
+	"types":[{"slot":1,"type":{"name":"fairy"}}],
+	"past_types":[{"generation":{"name":"generation-v"},"types":[{"slot":1,"type":{"name":"normal"}}]}],
+	"sprites":{"front_default":"today.png","front_shiny":"shiny.png","versions":{
+		"generation-i":{"red-blue":{"front_default":"red-blue.png"}},
+		"generation-ii":{"gold":{"front_default":"gold.png","front_shiny":"gold-shiny.png"}}}}}`
+
+func TestGame(t *testing.T) {
+	var p PokemonEndpoint
+	err := json.Unmarshal([]byte(clefairy), &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	red := Game{Version: "red", VersionGroup: "red-blue", Generation: 1}
+	gold := Game{Version: "gold", VersionGroup: "gold-silver", Generation: 2}
+	x := Game{Version: "x", VersionGroup: "x-y", Generation: 6}
+	cases := []struct {
+		game   Game
+		types  []string
+		sprite string
+	}{
+		{Game{}, []string{"fairy"}, "today.png"},
+		{red, []string{"normal"}, "red-blue.png"},
+		{gold, []string{"normal"}, "gold.png"},
+		{x, []string{"fairy"}, "today.png"},
+	}
+	for _, c := range cases {
+		if types := c.game.Types(p); !slices.Equal(types, c.types) {
+			t.Errorf("%q: expected types %v, got %v", c.game.Version, c.types, types)
+		}
+		if sprite := c.game.Sprite(p); sprite != c.sprite {
+			t.Errorf("%q: expected sprite %s, got %s", c.game.Version, c.sprite, sprite)
+		}
+	}
+
+	variants := []struct {
+		game    Game
+		variant string
+		sprite  string
+	}{
+		{gold, "front", "gold.png"},
+		{gold, "shiny", "gold-shiny.png"},
+		{red, "shiny", "shiny.png"},
+		{x, "gen1", "red-blue.png"},
+		{Game{}, "back", ""},
+		{Game{}, "side", ""},
+	}
+	for _, c := range variants {
+		sprite, err := c.game.SpriteVariant(p, c.variant)
+		if sprite != c.sprite || (err != nil) != (c.sprite == "") {
+			t.Errorf("%q %s: expected sprite %q, got %q %v", c.game.Version, c.variant, c.sprite, sprite, err)
+		}
+	}
+
+	if !red.HasVersionGroup("red-blue") || red.HasVersionGroup("platinum") || !(Game{}).HasVersionGroup("platinum") {
+		t.Error("unexpected version groups")
+	}
+	for name, want := range map[string]int{"generation-i": 1, "generation-iv": 4, "generation-ix": 9, "generation-x": 10, "gen-3": 0} {
+		if got := pokeapi.GenerationNumber(name); got != want {
+			t.Errorf("GenerationNumber(%q) = %d, want %d", name, got, want)
+		}
+	}
+}
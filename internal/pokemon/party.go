@@ -0,0 +1,226 @@
+package pokemon
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const PartySize = 6
+
+// Caught is one pokemon you caught. the Pokedex keeps a single entry per
+// species, so catching two magikarp gives two Caught with the same Species
+type Caught struct {
+	ID       int    `json:"id"`
+	Species  string `json:"species"`
+	Nickname string `json:"nickname,omitempty"`
+	Level    int    `json:"level"`
+	// HP lost in battle, a pokemon with as much damage as it has HP has fainted
+	Damage   int       `json:"damage,omitempty"`
+	Location string    `json:"location,omitempty"`
+	CaughtAt time.Time `json:"caught_at"`
+}
+
+// Name is the nickname if it has one, otherwise the species
+func (p Caught) Name() string {
+	if p.Nickname != "" {
+		return p.Nickname
+	}
+	return p.Species
+}
+
+// ValidNickname rejects nicknames that would be mistaken for an ID
+func ValidNickname(nickname string) error {
+	if strings.TrimSpace(nickname) != nickname || strings.ContainsAny(nickname, " \t") {
+		return errors.New("nicknames can't contain spaces")
+	}
+	if _, err := parseID(nickname); err == nil {
+		return errors.New("nicknames can't be numbers")
+	}
+	return nil
+}
+
+// Catch registers the species and adds a new pokemon, straight into the
+// party if there's room for it. a level below 1 is CatchLevel
+func (c *Pokedex) Catch(pokemon PokemonEndpoint, level int, location, nickname string) Caught {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.List[pokemon.Name] = pokemon
+	c.NextID++
+	caught := Caught{
+		ID:       c.NextID,
+		Species:  pokemon.Name,
+		Nickname: nickname,
+		Level:    CatchLevel,
+		Location: location,
+		CaughtAt: time.Now().UTC(),
+	}
+	if level > 0 {
+		caught.Level = min(level, MaxLevel)
+	}
+	c.Caught = append(c.Caught, caught)
+	if len(c.Party) < PartySize {
+		c.Party = append(c.Party, caught.ID)
+	}
+	return caught
+}
+
+// Find looks a caught pokemon up by ID ("3" or "#3"), nickname or species.
+// party members win over the rest when more than one matches
+func (c *Pokedex) Find(ref string) (Caught, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i, err := c.find(ref)
+	if err != nil {
+		return Caught{}, err
+	}
+	return c.Caught[i], nil
+}
+
+func (c *Pokedex) find(ref string) (int, error) {
+	if id, err := parseID(ref); err == nil {
+		for i, caught := range c.Caught {
+			if caught.ID == id {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("You don't have a pokemon with ID #%d", id)
+	}
+
+	matches := func(caught Caught) bool {
+		return strings.EqualFold(caught.Nickname, ref) || caught.Species == ref
+	}
+	for _, id := range c.Party {
+		i := c.index(id)
+		if i >= 0 && matches(c.Caught[i]) {
+			return i, nil
+		}
+	}
+	for i, caught := range c.Caught {
+		if matches(caught) {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("You don't have the pokemon: %s", ref)
+}
+
+func (c *Pokedex) index(id int) int {
+	for i, caught := range c.Caught {
+		if caught.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func parseID(ref string) (int, error) {
+	return strconv.Atoi(strings.TrimPrefix(ref, "#"))
+}
+
+// Instances returns every caught pokemon in the order they were caught
+func (c *Pokedex) Instances() []Caught {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.Caught)
+}
+
+// PartyMembers returns the party in slot order
+func (c *Pokedex) PartyMembers() []Caught {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	party := make([]Caught, 0, len(c.Party))
+	for _, id := range c.Party {
+		if i := c.index(id); i >= 0 {
+			party = append(party, c.Caught[i])
+		}
+	}
+	return party
+}
+
+func (c *Pokedex) AddToParty(ref string) (Caught, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i, err := c.find(ref)
+	if err != nil {
+		return Caught{}, err
+	}
+	caught := c.Caught[i]
+	if slices.Contains(c.Party, caught.ID) {
+		return Caught{}, fmt.Errorf("%s is already in your party", caught.Name())
+	}
+	if len(c.Party) >= PartySize {
+		return Caught{}, fmt.Errorf("Your party is full, remove a pokemon first")
+	}
+	c.Party = append(c.Party, caught.ID)
+	return caught, nil
+}
+
+func (c *Pokedex) RemoveFromParty(ref string) (Caught, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i, err := c.find(ref)
+	if err != nil {
+		return Caught{}, err
+	}
+	caught := c.Caught[i]
+	slot := slices.Index(c.Party, caught.ID)
+	if slot < 0 {
+		return Caught{}, fmt.Errorf("%s isn't in your party", caught.Name())
+	}
+	c.Party = slices.Delete(c.Party, slot, slot+1)
+	return caught, nil
+}
+
+// SwapParty swaps the pokemon in two party slots, counting from 1
+func (c *Pokedex) SwapParty(a, b int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, slot := range []int{a, b} {
+		if slot < 1 || slot > len(c.Party) {
+			return fmt.Errorf("no pokemon in party slot %d (your party has %d)", slot, len(c.Party))
+		}
+	}
+	c.Party[a-1], c.Party[b-1] = c.Party[b-1], c.Party[a-1]
+	return nil
+}
+
+// LevelUp raises a pokemon by one level and returns its new level
+func (c *Pokedex) LevelUp(id int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := c.index(id)
+	if i < 0 {
+		return 0
+	}
+	c.Caught[i].Level = min(c.Caught[i].Level+1, MaxLevel)
+	return c.Caught[i].Level
+}
+
+// SetLevel is for when a level comes from somewhere other than battling
+func (c *Pokedex) SetLevel(id, level int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := c.index(id)
+	if i < 0 {
+		return fmt.Errorf("You don't have a pokemon with ID #%d", id)
+	}
+	c.Caught[i].Level = max(min(level, MaxLevel), 1)
+	return nil
+}
+
+// Evolve turns a caught pokemon into what it evolved into and registers the
+// new species. the old species stays in the Pokedex, it was still caught
+func (c *Pokedex) Evolve(id int, to PokemonEndpoint) (Caught, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := c.index(id)
+	if i < 0 {
+		return Caught{}, fmt.Errorf("You don't have a pokemon with ID #%d", id)
+	}
+	c.List[to.Name] = to
+	c.Caught[i].Species = to.Name
+	return c.Caught[i], nil
+}
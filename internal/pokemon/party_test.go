@@ -0,0 +1,56 @@
+package pokemon
+
+import (
+	"testing"
+)
+
+func TestParty(t *testing.T) {
+	dex := newTestPokedex()
+	for i := 0; i < PartySize+1; i++ {
+		dex.Catch(PokemonEndpoint{Name: "magikarp"}, 0, "", "")
+	}
+	if len(dex.Instances()) != PartySize+1 || len(dex.List) != 1 {
+		t.Fatalf("expected %d magikarp of one species, got %d of %d", PartySize+1, len(dex.Instances()), len(dex.List))
+	}
+	if len(dex.PartyMembers()) != PartySize {
+		t.Errorf("expected a full party, got %d", len(dex.PartyMembers()))
+	}
+
+	_, err := dex.AddToParty("7")
+	if err == nil {
+		t.Error("added a pokemon to a full party")
+	}
+	_, err = dex.RemoveFromParty("#2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = dex.AddToParty("7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dex.SwapParty(1, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = dex.SwapParty(1, 7)
+	if err == nil {
+		t.Error("swapped with an empty slot")
+	}
+
+	var ids []int
+	for _, p := range dex.PartyMembers() {
+		ids = append(ids, p.ID)
+	}
+	want := []int{7, 3, 4, 5, 6, 1}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("expected party %v, got %v", want, ids)
+		}
+	}
+
+	// a species finds the first one in the party, not the first one caught
+	found, err := dex.Find("magikarp")
+	if err != nil || found.ID != 7 {
+		t.Errorf("expected magikarp #7, got %+v (%v)", found, err)
+	}
+}
@@ -1,50 +1,46 @@
 package pokemon
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
-	"io"
-	"math/rand"
-	"net/http"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"github.com/srijan-raghavula/pokedex/internal/snapshot"
+	"strings"
 )
 
-func pokemonInfo(name string) (PokemonEndpoint, error) {
-	var pokemon PokemonEndpoint
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%s", name)
-	res, err := http.Get(url)
-	if err != nil {
-		return pokemon, err
-	}
-	if sc := res.StatusCode; sc > 299 {
-		if sc == 404 {
-			return pokemon, errors.New("invalid pokemon name (check spelling)")
-		}
-		return pokemon, errors.New(fmt.Sprintf("status code: %d", sc))
-	}
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return pokemon, err
+// UnknownPokemonError is a name PokeAPI has no pokemon for
+type UnknownPokemonError struct {
+	Name string
+	// the names it was probably meant to be, left for whoever knows every
+	// pokemon to fill in
+	Suggestions []string
+}
+
+func (e *UnknownPokemonError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return "invalid pokemon name (check spelling)"
 	}
-	defer res.Body.Close()
-	json.Unmarshal(body, &pokemon)
+	return fmt.Sprintf("invalid pokemon name %s, did you mean %s?", e.Name, strings.Join(e.Suggestions, " or "))
+}
 
-	return pokemon, nil
+func (e *UnknownPokemonError) Unwrap() error {
+	return pokeapi.ErrNotFound
 }
 
-func IsCaught(name string) (bool, error) {
-	pokemonInfo, err := pokemonInfo(name)
-	if err != nil {
-		return false, err
-	}
-	// 701 because I want a number in [0, n)
-	// max BaseExperience so far is 635 for Blissey
-	chance := rand.Intn(701)
-	if chance >= pokemonInfo.BaseExperience {
-		Pokemons.Add(pokemonInfo.Name, pokemonInfo)
-		return true, nil
+func pokemonInfo(ctx context.Context, client *pokeapi.Client, name string) (PokemonEndpoint, error) {
+	var pokemon PokemonEndpoint
+	err := client.GetJSON(ctx, client.URL("pokemon", name), &pokemon)
+	// a name missing from an offline snapshot might still be spelled right
+	if errors.Is(err, pokeapi.ErrNotFound) && !snapshot.IsMissing(err) {
+		return pokemon, &UnknownPokemonError{Name: name}
 	}
-	return false, nil
+	return pokemon, err
+}
+
+// Info looks a pokemon up by name
+func Info(ctx context.Context, client *pokeapi.Client, name string) (PokemonEndpoint, error) {
+	return pokemonInfo(ctx, client, name)
 }
 
 type PokemonEndpoint struct {
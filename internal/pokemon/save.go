@@ -0,0 +1,185 @@
+package pokemon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// bump this whenever saveData changes shape and add a migration in Load
+const saveVersion = 4
+
+var ErrCorruptSave = errors.New("save file is corrupted")
+
+type saveFile struct {
+	Version  int             `json:"version"`
+	Checksum string          `json:"checksum"`
+	Data     json.RawMessage `json:"data"`
+}
+
+type saveData struct {
+	Pokemons map[string]PokemonEndpoint `json:"pokemons"`
+	// added in version 3
+	Caught []Caught `json:"caught"`
+	Party  []int    `json:"party"`
+	NextID int      `json:"next_id"`
+	// added in version 4
+	Bag map[string]int `json:"bag"`
+	// version 2 only, levels by species before there were caught pokemon
+	Progress map[string]struct {
+		Level int `json:"level"`
+	} `json:"progress,omitempty"`
+}
+
+func DefaultSavePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pokedex", "save.json"), nil
+}
+
+func (c *Pokedex) Save(path string) error {
+	c.mu.Lock()
+	data, err := json.Marshal(saveData{
+		Pokemons: c.List,
+		Caught:   c.Caught,
+		Party:    c.Party,
+		NextID:   c.NextID,
+		Bag:      c.Bag,
+	})
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(saveFile{
+		Version:  saveVersion,
+		Checksum: checksum(data),
+		Data:     data,
+	})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, body)
+}
+
+func (c *Pokedex) Load(path string) error {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var file saveFile
+	err = json.Unmarshal(body, &file)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+	if file.Version < 1 || file.Version > saveVersion {
+		return fmt.Errorf("unsupported save file version: %d", file.Version)
+	}
+
+	// the checksum is taken over the compact form so that a
+	// pretty-printed save file still verifies
+	var data bytes.Buffer
+	err = json.Compact(&data, file.Data)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+	if checksum(data.Bytes()) != file.Checksum {
+		return fmt.Errorf("%w: checksum mismatch", ErrCorruptSave)
+	}
+
+	var loaded saveData
+	err = json.Unmarshal(data.Bytes(), &loaded)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorruptSave, err)
+	}
+	migrate(file.Version, &loaded)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.List = loaded.Pokemons
+	c.Caught = loaded.Caught
+	c.Party = loaded.Party
+	c.NextID = loaded.NextID
+	c.Bag = loaded.Bag
+	return nil
+}
+
+// brings data saved by an older version up to the current one
+func migrate(version int, data *saveData) {
+	if data.Pokemons == nil {
+		data.Pokemons = make(map[string]PokemonEndpoint)
+	}
+	if version < 3 {
+		// before version 3 there was one pokemon per species, so each species
+		// becomes a caught pokemon at the level version 2 tracked for it
+		names := make([]string, 0, len(data.Pokemons))
+		for name := range data.Pokemons {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			data.NextID++
+			data.Caught = append(data.Caught, Caught{
+				ID:      data.NextID,
+				Species: name,
+				Level:   max(data.Progress[name].Level, CatchLevel),
+			})
+			if len(data.Party) < PartySize {
+				data.Party = append(data.Party, data.NextID)
+			}
+		}
+		data.Progress = nil
+	}
+	if version < 4 {
+		data.Bag = starterBag()
+	}
+	if data.Bag == nil {
+		data.Bag = make(map[string]int)
+	}
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writes to a temp file in the same directory and renames it over path,
+// so a crash mid-write never leaves a half written save behind
+func writeFileAtomic(path string, body []byte) error {
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(body)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Sync()
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,133 @@
+package pokemon
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newTestPokedex() Pokedex {
+	return Pokedex{
+		mu:   &sync.Mutex{},
+		List: make(map[string]PokemonEndpoint),
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "save.json")
+
+	dex := newTestPokedex()
+	caught := dex.Catch(PokemonEndpoint{ID: 25, Name: "pikachu", BaseExperience: 112}, 0, "", "sparky")
+	dex.LevelUp(caught.ID)
+	err := dex.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := newTestPokedex()
+	err = loaded.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pikachu, err := loaded.Get("pikachu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pikachu.ID != 25 || pikachu.BaseExperience != 112 {
+		t.Errorf("loaded pokemon differs: %+v", pikachu)
+	}
+	sparky, err := loaded.Find("sparky")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sparky.Level != CatchLevel+1 || sparky.Species != "pikachu" || !sparky.CaughtAt.Equal(caught.CaughtAt) {
+		t.Errorf("loaded caught pokemon differs: %+v", sparky)
+	}
+	if party := loaded.PartyMembers(); len(party) != 1 || party[0].ID != caught.ID {
+		t.Errorf("unexpected party: %+v", party)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the save file to remain, found %d entries", len(entries))
+	}
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+
+	dex := newTestPokedex()
+	dex.Add("pikachu", PokemonEndpoint{ID: 25, Name: "pikachu"})
+	path := filepath.Join(dir, "save.json")
+	err := dex.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string][]byte{
+		"truncated": body[:len(body)/2],
+		"tampered":  []byte(strings.Replace(string(body), `"id":25`, `"id":26`, 1)),
+		"garbage":   []byte("not a save file"),
+	}
+	for name, contents := range cases {
+		p := filepath.Join(dir, name+".json")
+		err := os.WriteFile(p, contents, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded := newTestPokedex()
+		err = loaded.Load(p)
+		if !errors.Is(err, ErrCorruptSave) {
+			t.Errorf("%s: expected ErrCorruptSave, got %v", name, err)
+		}
+	}
+}
+
+func TestLoadOldVersions(t *testing.T) {
+	cases := map[int]string{
+		1: `{"pokemons":{"pikachu":{"id":25,"name":"pikachu"},"magikarp":{"id":129,"name":"magikarp"}}}`,
+		2: `{"pokemons":{"pikachu":{"id":25,"name":"pikachu"},"magikarp":{"id":129,"name":"magikarp"}},"progress":{"pikachu":{"level":12}}}`,
+	}
+	wantPikachu := map[int]int{1: CatchLevel, 2: 12}
+	for version, data := range cases {
+		body, err := json.Marshal(saveFile{Version: version, Checksum: checksum([]byte(data)), Data: []byte(data)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "save.json")
+		err = os.WriteFile(path, body, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		loaded := newTestPokedex()
+		err = loaded.Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		party := loaded.PartyMembers()
+		if len(party) != 2 || party[0].Species != "magikarp" || party[1].Species != "pikachu" {
+			t.Fatalf("version %d: unexpected party %+v", version, party)
+		}
+		if party[0].Level != CatchLevel || party[1].Level != wantPikachu[version] {
+			t.Errorf("version %d: unexpected levels %+v", version, party)
+		}
+		if loaded.Count("poke-ball") != StarterBag["poke-ball"] {
+			t.Errorf("version %d: expected the starter bag, got %v", version, loaded.Items())
+		}
+		if caught := loaded.Catch(PokemonEndpoint{Name: "pikachu"}, 0, "", ""); caught.ID != 3 {
+			t.Errorf("version %d: expected the next pokemon caught to be #3, got #%d", version, caught.ID)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type Format string
+
+const (
+	Text  Format = "text"
+	JSON  Format = "json"
+	Table Format = "table"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case Text, JSON, Table:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown output format %q (use text, json or table)", s)
+}
+
+// Texter is implemented by results that have a hand written text form.
+// results without one are printed as a table in text mode too
+type Texter interface {
+	Text(w io.Writer) error
+}
+
+func Render(w io.Writer, format Format, v any) error {
+	if v == nil {
+		return nil
+	}
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case Table:
+		return table(w, v)
+	}
+	if texter, ok := v.(Texter); ok {
+		return texter.Text(w)
+	}
+	return table(w, v)
+}
+
+// table prints v as yaml-ish "key: value" lines, using the json field names
+// so that both formats describe the same schema
+func table(w io.Writer, v any) error {
+	var b strings.Builder
+	writeValue(&b, reflect.ValueOf(v), 0)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeValue(b *strings.Builder, v reflect.Value, depth int) {
+	v = indirect(v)
+	switch v.Kind() {
+	case reflect.Struct:
+		fields := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := fields.Field(i)
+			if field.Anonymous && field.Tag.Get("json") == "" && indirect(v.Field(i)).Kind() == reflect.Struct {
+				// embedded structs are flattened, like encoding/json does
+				writeValue(b, v.Field(i), depth)
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			name, omitEmpty := fieldName(field)
+			if name == "-" || (omitEmpty && v.Field(i).IsZero()) {
+				continue
+			}
+			writeEntry(b, name, v.Field(i), depth)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, k := range keys {
+			writeEntry(b, fmt.Sprint(k), v.MapIndex(k), depth)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			item := indirect(v.Index(i))
+			indent(b, depth)
+			if isScalar(item) {
+				fmt.Fprintf(b, "- %s\n", scalar(item))
+				continue
+			}
+			// nested entries line up under the dash
+			b.WriteString("-\n")
+			writeValue(b, item, depth+1)
+		}
+	default:
+		indent(b, depth)
+		b.WriteString(scalar(v) + "\n")
+	}
+}
+
+func writeEntry(b *strings.Builder, name string, v reflect.Value, depth int) {
+	v = indirect(v)
+	indent(b, depth)
+	if isScalar(v) {
+		fmt.Fprintf(b, "%s: %s\n", name, scalar(v))
+		return
+	}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+		if v.Kind() == reflect.Map {
+			fmt.Fprintf(b, "%s: {}\n", name)
+		} else {
+			fmt.Fprintf(b, "%s: []\n", name)
+		}
+		return
+	}
+	fmt.Fprintf(b, "%s:\n", name)
+	writeValue(b, v, depth+1)
+}
+
+func fieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "" {
+		return f.Name, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, strings.Contains(opts, "omitempty")
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isScalar(v reflect.Value) bool {
+	if v.IsValid() && v.CanInterface() {
+		// things like time.Time print better as themselves
+		if _, ok := v.Interface().(fmt.Stringer); ok {
+			return true
+		}
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return false
+	}
+	return true
+}
+
+func scalar(v reflect.Value) string {
+	if !v.IsValid() || ((v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil()) {
+		return "null"
+	}
+	if stringer, ok := v.Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func indent(b *strings.Builder, depth int) {
+	b.WriteString(strings.Repeat("  ", depth))
+}
@@ -0,0 +1,118 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+type stat struct {
+	Name     string `json:"name"`
+	BaseStat int    `json:"base_stat"`
+}
+
+type result struct {
+	Name    string   `json:"name"`
+	Types   []string `json:"types"`
+	Stats   []stat   `json:"stats"`
+	Note    string   `json:"note,omitempty"`
+	Missing []string `json:"missing"`
+}
+
+func (r result) Text(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Pokemon: %s\n", r.Name)
+	return err
+}
+
+func TestRender(t *testing.T) {
+	r := result{
+		Name:  "pikachu",
+		Types: []string{"electric"},
+		Stats: []stat{{"hp", 35}, {"speed", 90}},
+	}
+	cases := []struct {
+		format Format
+		want   string
+	}{
+		{
+			format: Text,
+			want:   "Pokemon: pikachu\n",
+		},
+		{
+			format: Table,
+			want: `name: pikachu
+types:
+  - electric
+stats:
+  -
+    name: hp
+    base_stat: 35
+  -
+    name: speed
+    base_stat: 90
+missing: []
+`,
+		},
+		{
+			format: JSON,
+			want: `{
+  "name": "pikachu",
+  "types": [
+    "electric"
+  ],
+  "stats": [
+    {
+      "name": "hp",
+      "base_stat": 35
+    },
+    {
+      "name": "speed",
+      "base_stat": 90
+    }
+  ],
+  "missing": null
+}
+`,
+		},
+	}
+
+	for _, testCase := range cases {
+		var b strings.Builder
+		err := Render(&b, testCase.format, r)
+		if err != nil {
+			t.Errorf("%s: %v", testCase.format, err)
+			continue
+		}
+		if b.String() != testCase.want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", testCase.format, testCase.want, b.String())
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("JSON")
+	if err != nil || f != JSON {
+		t.Errorf("expected json, got %q, %v", f, err)
+	}
+	_, err = ParseFormat("xml")
+	if err == nil {
+		t.Error("expected an error for xml")
+	}
+}
+
+func TestRenderEmbedded(t *testing.T) {
+	type outer struct {
+		Seed int `json:"seed"`
+		stat
+	}
+	var b strings.Builder
+	err := Render(&b, Table, outer{Seed: 7, stat: stat{"hp", 35}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "seed: 7\nname: hp\nbase_stat: 35\n"
+	if b.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, b.String())
+	}
+}
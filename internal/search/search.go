@@ -0,0 +1,163 @@
+// Package search finds PokeAPI names by how they start or how they're
+// spelled, from an index of PokeAPI's list endpoints kept on disk.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/fuzzy"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// the kinds of names in the index, by their PokeAPI endpoint
+const (
+	Pokemon      = "pokemon"
+	LocationArea = "location-area"
+	Move         = "move"
+	Item         = "item"
+)
+
+var Kinds = []string{Pokemon, LocationArea, Move, Item}
+
+// MaxAge is how long an index is used before it's built again, PokeAPI
+// only gains names with new games
+const MaxAge = 7 * 24 * time.Hour
+
+// big enough to get every name of a kind in one page
+const listLimit = 100000
+
+// how many names a misspelled one gets suggested
+const maxSuggestions = 3
+
+type Index struct {
+	Built time.Time `json:"built"`
+	// the names of each kind, sorted
+	Names map[string][]string `json:"names"`
+}
+
+type Match struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// how the name matched: prefix, word or spelling
+	By string `json:"by"`
+	// how many letters it takes to turn the term into the name, for
+	// spelling matches
+	Distance int `json:"distance,omitempty"`
+}
+
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pokedex", "search.json"), nil
+}
+
+// Build fetches every name of every kind from PokeAPI
+func Build(ctx context.Context, client *pokeapi.Client) (*Index, error) {
+	index := &Index{Built: time.Now().UTC(), Names: make(map[string][]string)}
+	for _, kind := range Kinds {
+		var list pokeapi.ResourceList
+		err := client.GetJSON(ctx, fmt.Sprintf("%s?offset=0&limit=%d", client.URL(kind), listLimit), &list)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(list.Results))
+		for _, result := range list.Results {
+			names = append(names, result.Name)
+		}
+		sort.Strings(names)
+		index.Names[kind] = names
+	}
+	return index, nil
+}
+
+func Load(path string) (*Index, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var index Index
+	err = json.Unmarshal(body, &index)
+	if err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+func (i *Index) Save(path string) error {
+	body, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, body, 0o644)
+}
+
+// Stale reports whether the index is older than MaxAge
+func (i *Index) Stale(now time.Time) bool {
+	return now.Sub(i.Built) > MaxAge
+}
+
+// Search finds the names of the given kinds, or of every kind, that start
+// with term, then the ones with a word that starts with it, like "coronet"
+// in mt-coronet-2f, then the ones spelled like it. at most limit are returned
+func (i *Index) Search(term string, kinds []string, limit int) []Match {
+	if len(kinds) == 0 {
+		kinds = Kinds
+	}
+	term = strings.ToLower(term)
+	threshold := fuzzy.Threshold(term)
+	var matches []Match
+	for _, kind := range kinds {
+		for _, name := range i.Names[kind] {
+			switch {
+			case strings.HasPrefix(name, term):
+				matches = append(matches, Match{Kind: kind, Name: name, By: "prefix"})
+			case strings.Contains(name, "-"+term):
+				matches = append(matches, Match{Kind: kind, Name: name, By: "word"})
+			default:
+				if d := fuzzy.Distance(term, name); d <= threshold {
+					matches = append(matches, Match{Kind: kind, Name: name, By: "spelling", Distance: d})
+				}
+			}
+		}
+	}
+	rank := map[string]int{"prefix": 0, "word": 1, "spelling": 2}
+	sort.SliceStable(matches, func(a, b int) bool {
+		x, y := matches[a], matches[b]
+		if rank[x.By] != rank[y.By] {
+			return rank[x.By] < rank[y.By]
+		}
+		if x.Distance != y.Distance {
+			return x.Distance < y.Distance
+		}
+		// the shortest names are the closest to what was typed
+		if len(x.Name) != len(y.Name) {
+			return len(x.Name) < len(y.Name)
+		}
+		return x.Name < y.Name
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Suggest is the few names of a kind closest to a misspelled one
+func (i *Index) Suggest(kind, word string) []string {
+	suggestions := fuzzy.Suggest(word, i.Names[kind], fuzzy.Threshold(word))
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
@@ -0,0 +1,63 @@
+package search
+
+import (
+	"context"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi/pokeapitest"
+	"github.com/srijan-raghavula/pokedex/internal/pokecache"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSearch(t *testing.T) {
+	server := pokeapitest.NewServer(t)
+	client := pokeapi.NewClient(server.BaseURL, time.Second, pokecache.NewCache(time.Minute))
+	index, err := Build(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(index.Names[Pokemon]) != 7 || len(index.Names[LocationArea]) != 45 || index.Names[Item][0] != "great-ball" {
+		t.Errorf("unexpected index: %v", index.Names)
+	}
+
+	names := func(matches []Match) []string {
+		var names []string
+		for _, match := range matches {
+			names = append(names, match.Kind+":"+match.Name)
+		}
+		return names
+	}
+	if got := names(index.Search("thunder", nil, 4)); !slices.Equal(got, []string{"move:thunder", "move:thunderbolt", "move:thunder-wave", "move:thunder-shock"}) {
+		t.Errorf("unexpected prefix matches: %v", got)
+	}
+	if got := names(index.Search("stone", nil, 10)); !slices.Equal(got, []string{"item:thunder-stone"}) {
+		t.Errorf("unexpected word matches: %v", got)
+	}
+	if got := names(index.Search("Pikachoo", []string{Pokemon}, 10)); !slices.Equal(got, []string{"pokemon:pikachu"}) {
+		t.Errorf("unexpected spelling matches: %v", got)
+	}
+	if got := index.Suggest(Pokemon, "magikrap"); !slices.Equal(got, []string{"magikarp"}) {
+		t.Errorf("unexpected suggestions: %v", got)
+	}
+	if got := index.Suggest(Move, "tackel"); !slices.Equal(got, []string{"tackle"}) {
+		t.Errorf("unexpected suggestions: %v", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "search.json")
+	err = index.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(loaded.Names[Move], index.Names[Move]) || !loaded.Built.Equal(index.Built) {
+		t.Errorf("expected the saved index back, got %+v", loaded)
+	}
+	if loaded.Stale(time.Now()) || !loaded.Stale(time.Now().Add(MaxAge+time.Hour)) {
+		t.Error("expected the index to go stale after MaxAge")
+	}
+}
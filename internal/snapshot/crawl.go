@@ -0,0 +1,376 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+)
+
+type FetchFunc func(url string) ([]byte, error)
+
+type page struct {
+	Count   int    `json:"count"`
+	Next    string `json:"next"`
+	Results []struct {
+		Name string `json:"name"`
+	} `json:"results"`
+}
+
+type pokemon struct {
+	Species struct {
+		Name string `json:"name"`
+	} `json:"species"`
+	Moves []struct {
+		Move struct {
+			Name string `json:"name"`
+		} `json:"move"`
+		VersionGroupDetails []struct {
+			MoveLearnMethod struct {
+				Name string `json:"name"`
+			} `json:"move_learn_method"`
+		} `json:"version_group_details"`
+	} `json:"moves"`
+	Types     []pokemonType `json:"types"`
+	PastTypes []struct {
+		Types []pokemonType `json:"types"`
+	} `json:"past_types"`
+}
+
+type pokemonType struct {
+	Type struct {
+		Name string `json:"name"`
+	} `json:"type"`
+}
+
+type species struct {
+	EvolutionChain *struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	Varieties []struct {
+		IsDefault bool `json:"is_default"`
+		Pokemon   struct {
+			Name string `json:"name"`
+		} `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+type chainLink struct {
+	Species struct {
+		Name string `json:"name"`
+	} `json:"species"`
+	EvolutionDetails []struct {
+		Item *struct {
+			Name string `json:"name"`
+		} `json:"item"`
+	} `json:"evolution_details"`
+	EvolvesTo []chainLink `json:"evolves_to"`
+}
+
+type area struct {
+	PokemonEncounters []struct {
+		Pokemon struct {
+			Name string `json:"name"`
+		} `json:"pokemon"`
+		VersionDetails []struct {
+			Version struct {
+				Name string `json:"name"`
+			} `json:"version"`
+		} `json:"version_details"`
+	} `json:"pokemon_encounters"`
+}
+
+type version struct {
+	VersionGroup struct {
+		Name string `json:"name"`
+	} `json:"version_group"`
+}
+
+// Crawl walks the location-area list pages the same way map does, and stores
+// every page, every location area and every pokemon found in those areas
+// along with its species, its types, the moves it learns by level up and
+// what it evolves from and into, and the versions the areas list.
+// items are the ones a bag can hold, the items evolutions need are crawled
+// along with them. maxAreas stops the crawl early, 0 crawls everything
+func Crawl(w *Writer, fetch FetchFunc, baseURL string, maxAreas int, items []string, progress func(done, total int)) error {
+	for _, name := range items {
+		err := crawlItem(w, fetch, baseURL, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	next := baseURL + "/location-area"
+	done := 0
+	for next != "" {
+		body, err := fetch(next)
+		if err != nil {
+			return err
+		}
+		err = w.Add(next, body)
+		if err != nil {
+			return err
+		}
+		var listPage page
+		err = json.Unmarshal(body, &listPage)
+		if err != nil {
+			return fmt.Errorf("%s: %w", next, err)
+		}
+
+		total := listPage.Count
+		if maxAreas > 0 && maxAreas < total {
+			total = maxAreas
+		}
+		for _, result := range listPage.Results {
+			if done >= total {
+				return nil
+			}
+			err := crawlArea(w, fetch, baseURL, result.Name)
+			if err != nil {
+				return err
+			}
+			done++
+			if progress != nil {
+				progress(done, total)
+			}
+		}
+		next = listPage.Next
+	}
+	return nil
+}
+
+func crawlArea(w *Writer, fetch FetchFunc, baseURL, name string) error {
+	areaURL := fmt.Sprintf("%s/location-area/%s", baseURL, name)
+	body, err := fetch(areaURL)
+	if err != nil {
+		return err
+	}
+	err = w.Add(areaURL, body)
+	if err != nil {
+		return err
+	}
+
+	var locationArea area
+	err = json.Unmarshal(body, &locationArea)
+	if err != nil {
+		return fmt.Errorf("%s: %w", areaURL, err)
+	}
+	for _, encounter := range locationArea.PokemonEncounters {
+		for _, details := range encounter.VersionDetails {
+			err := crawlVersion(w, fetch, baseURL, details.Version.Name)
+			if err != nil {
+				return err
+			}
+		}
+		err := crawlPokemon(w, fetch, baseURL, encounter.Pokemon.Name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func crawlPokemon(w *Writer, fetch FetchFunc, baseURL, name string) error {
+	pokemonURL := fmt.Sprintf("%s/pokemon/%s", baseURL, name)
+	if w.Has(pokemonURL) {
+		return nil
+	}
+	body, err := fetch(pokemonURL)
+	if err != nil {
+		return err
+	}
+	err = w.Add(pokemonURL, body)
+	if err != nil {
+		return err
+	}
+
+	var p pokemon
+	err = json.Unmarshal(body, &p)
+	if err != nil {
+		return fmt.Errorf("%s: %w", pokemonURL, err)
+	}
+	err = crawlSpecies(w, fetch, baseURL, p.Species.Name)
+	if err != nil {
+		return err
+	}
+	err = crawlTypes(w, fetch, baseURL, p)
+	if err != nil {
+		return err
+	}
+	return crawlMoves(w, fetch, baseURL, p)
+}
+
+// matchups and battles need the damage relations of every type a pokemon
+// has, or had in older games
+func crawlTypes(w *Writer, fetch FetchFunc, baseURL string, p pokemon) error {
+	types := p.Types
+	for _, past := range p.PastTypes {
+		types = append(types, past.Types...)
+	}
+	for _, t := range types {
+		typeURL := fmt.Sprintf("%s/type/%s", baseURL, t.Type.Name)
+		if w.Has(typeURL) {
+			continue
+		}
+		body, err := fetch(typeURL)
+		if err != nil {
+			return err
+		}
+		err = w.Add(typeURL, body)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// catching needs the species of a pokemon for its capture rate, and
+// evolving needs its evolution chain and the pokemon it evolves into
+func crawlSpecies(w *Writer, fetch FetchFunc, baseURL, name string) error {
+	speciesURL := fmt.Sprintf("%s/pokemon-species/%s", baseURL, name)
+	if name == "" || w.Has(speciesURL) {
+		return nil
+	}
+	body, err := fetch(speciesURL)
+	if err != nil {
+		return err
+	}
+	err = w.Add(speciesURL, body)
+	if err != nil {
+		return err
+	}
+
+	var s species
+	err = json.Unmarshal(body, &s)
+	if err != nil {
+		return fmt.Errorf("%s: %w", speciesURL, err)
+	}
+	for _, variety := range s.Varieties {
+		if !variety.IsDefault {
+			continue
+		}
+		err := crawlPokemon(w, fetch, baseURL, variety.Pokemon.Name)
+		if err != nil {
+			return err
+		}
+	}
+	if s.EvolutionChain == nil || s.EvolutionChain.URL == "" || w.Has(s.EvolutionChain.URL) {
+		return nil
+	}
+	body, err = fetch(s.EvolutionChain.URL)
+	if err != nil {
+		return err
+	}
+	err = w.Add(s.EvolutionChain.URL, body)
+	if err != nil {
+		return err
+	}
+	var chain struct {
+		Chain chainLink `json:"chain"`
+	}
+	err = json.Unmarshal(body, &chain)
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.EvolutionChain.URL, err)
+	}
+	return crawlChain(w, fetch, baseURL, chain.Chain)
+}
+
+// every species in a chain, from its earliest stage, and the items
+// they evolve with
+func crawlChain(w *Writer, fetch FetchFunc, baseURL string, link chainLink) error {
+	err := crawlSpecies(w, fetch, baseURL, link.Species.Name)
+	if err != nil {
+		return err
+	}
+	for _, detail := range link.EvolutionDetails {
+		if detail.Item == nil {
+			continue
+		}
+		err := crawlItem(w, fetch, baseURL, detail.Item.Name)
+		if err != nil {
+			return err
+		}
+	}
+	for _, next := range link.EvolvesTo {
+		err := crawlChain(w, fetch, baseURL, next)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// battles pick from the moves a pokemon learns by level up. a move PokeAPI
+// has no page for is left out, like battles leave it out
+func crawlMoves(w *Writer, fetch FetchFunc, baseURL string, p pokemon) error {
+	for _, move := range p.Moves {
+		levelUp := false
+		for _, detail := range move.VersionGroupDetails {
+			levelUp = levelUp || detail.MoveLearnMethod.Name == "level-up"
+		}
+		moveURL := fmt.Sprintf("%s/move/%s", baseURL, move.Move.Name)
+		if !levelUp || w.Has(moveURL) {
+			continue
+		}
+		body, err := fetch(moveURL)
+		if errors.Is(err, pokeapi.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		err = w.Add(moveURL, body)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// the bag shows what an item does. it works without, so an item PokeAPI
+// has no page for is left out
+func crawlItem(w *Writer, fetch FetchFunc, baseURL, name string) error {
+	itemURL := fmt.Sprintf("%s/item/%s", baseURL, name)
+	if name == "" || w.Has(itemURL) {
+		return nil
+	}
+	body, err := fetch(itemURL)
+	if errors.Is(err, pokeapi.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return w.Add(itemURL, body)
+}
+
+// picking a version needs the version and its version group
+func crawlVersion(w *Writer, fetch FetchFunc, baseURL, name string) error {
+	versionURL := fmt.Sprintf("%s/version/%s", baseURL, name)
+	if name == "" || w.Has(versionURL) {
+		return nil
+	}
+	body, err := fetch(versionURL)
+	if err != nil {
+		return err
+	}
+	err = w.Add(versionURL, body)
+	if err != nil {
+		return err
+	}
+
+	var v version
+	err = json.Unmarshal(body, &v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", versionURL, err)
+	}
+	groupURL := fmt.Sprintf("%s/version-group/%s", baseURL, v.VersionGroup.Name)
+	if v.VersionGroup.Name == "" || w.Has(groupURL) {
+		return nil
+	}
+	body, err = fetch(groupURL)
+	if err != nil {
+		return err
+	}
+	return w.Add(groupURL, body)
+}
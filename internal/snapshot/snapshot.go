@@ -0,0 +1,195 @@
+package snapshot
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ErrMissing = errors.New("not in the offline snapshot")
+
+// IsMissing reports whether err is the 404 an Archive answers for a
+// resource it doesn't have, which PokeAPI itself might still have
+func IsMissing(err error) bool {
+	if errors.Is(err, ErrMissing) {
+		return true
+	}
+	var statusErr *pokeapi.StatusError
+	return errors.As(err, &statusErr) && strings.HasSuffix(statusErr.Message, ErrMissing.Error())
+}
+
+// a snapshot is a zip archive with one entry per PokeAPI resource,
+// named after the resource path, e.g. "pokemon/pikachu.json"
+type Archive struct {
+	zr    *zip.ReadCloser
+	files map[string]*zip.File
+}
+
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pokedex", "snapshot.zip"), nil
+}
+
+func Open(path string) (*Archive, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	archive := &Archive{
+		zr:    zr,
+		files: make(map[string]*zip.File),
+	}
+	for _, f := range zr.File {
+		archive.files[f.Name] = f
+	}
+	return archive, nil
+}
+
+func (a *Archive) Close() error {
+	return a.zr.Close()
+}
+
+func (a *Archive) Get(rawURL string) ([]byte, error) {
+	key := Key(rawURL)
+	f, ok := a.files[key+".json"]
+	if !ok {
+		return nil, fmt.Errorf("%s is %w", key, ErrMissing)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// RoundTrip lets an Archive stand in for the network in an http.Client.
+// a resource the snapshot doesn't have is a 404, like PokeAPI answers for
+// one that doesn't exist
+func (a *Archive) RoundTrip(req *http.Request) (*http.Response, error) {
+	status := http.StatusOK
+	body, err := a.Get(req.URL.String())
+	if errors.Is(err, ErrMissing) {
+		status = http.StatusNotFound
+		body = []byte(err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	contentType := "application/json"
+	if status != http.StatusOK {
+		contentType = "text/plain; charset=utf-8"
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {contentType}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+type Writer struct {
+	path string
+	tmp  *os.File
+	zw   *zip.Writer
+	seen map[string]bool
+}
+
+func Create(path string) (*Writer, error) {
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{
+		path: path,
+		tmp:  tmp,
+		zw:   zip.NewWriter(tmp),
+		seen: make(map[string]bool),
+	}, nil
+}
+
+func (w *Writer) Has(rawURL string) bool {
+	return w.seen[Key(rawURL)]
+}
+
+func (w *Writer) Add(rawURL string, body []byte) error {
+	key := Key(rawURL)
+	if w.seen[key] {
+		return nil
+	}
+	f, err := w.zw.Create(key + ".json")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(body)
+	if err != nil {
+		return err
+	}
+	w.seen[key] = true
+	return nil
+}
+
+// Close finishes the archive and moves it into place
+func (w *Writer) Close() error {
+	defer os.Remove(w.tmp.Name())
+	err := w.zw.Close()
+	if err != nil {
+		w.tmp.Close()
+		return err
+	}
+	err = w.tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(w.tmp.Name(), w.path)
+}
+
+// Abort throws away everything written so far
+func (w *Writer) Abort() {
+	w.tmp.Close()
+	os.Remove(w.tmp.Name())
+}
+
+// Key turns a PokeAPI url into the name of its entry in the archive.
+// list pages keep their offset and limit, everything else is keyed by path only
+func Key(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	path := strings.Trim(u.Path, "/")
+	path = strings.TrimPrefix(path, "api/v2/")
+	if strings.Contains(path, "/") {
+		return path
+	}
+
+	query := u.Query()
+	offset, limit := query.Get("offset"), query.Get("limit")
+	if offset == "" {
+		offset = "0"
+	}
+	if limit == "" {
+		limit = "20"
+	}
+	return fmt.Sprintf("%s/offset=%s&limit=%s", path, offset, limit)
+}
@@ -0,0 +1,129 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	cases := map[string]string{
+		"https://pokeapi.co/api/v2/location-area":                        "location-area/offset=0&limit=20",
+		"https://pokeapi.co/api/v2/location-area?offset=20&limit=20":     "location-area/offset=20&limit=20",
+		"https://pokeapi.co/api/v2/location-area/canalave-city-area":     "location-area/canalave-city-area",
+		"https://pokeapi.co/api/v2/location-area/canalave-city-area?a=b": "location-area/canalave-city-area",
+		"https://pokeapi.co/api/v2/pokemon/pikachu/":                     "pokemon/pikachu",
+		"http://127.0.0.1:8080/api/v2/location-area/?offset=40&limit=20": "location-area/offset=40&limit=20",
+	}
+	for in, want := range cases {
+		if got := Key(in); got != want {
+			t.Errorf("Key(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCrawlAndServe(t *testing.T) {
+	const base = "https://pokeapi.co/api/v2"
+	// thunder is only learned from a machine, and the fake PokeAPI has no volt-tackle
+	const pikachuMoves = `{"move":{"name":"thunder-shock"},"version_group_details":[{"move_learn_method":{"name":"level-up"}}]},` +
+		`{"move":{"name":"thunder"},"version_group_details":[{"move_learn_method":{"name":"machine"}}]},` +
+		`{"move":{"name":"volt-tackle"},"version_group_details":[{"move_learn_method":{"name":"level-up"}}]}`
+	responses := map[string]string{
+		base + "/location-area":                  `{"count":3,"next":"` + base + `/location-area?offset=2&limit=2","results":[{"name":"a"},{"name":"b"}]}`,
+		base + "/location-area?offset=2&limit=2": `{"count":3,"next":"","results":[{"name":"c"}]}`,
+		base + "/location-area/a":                `{"pokemon_encounters":[{"pokemon":{"name":"pikachu"}}]}`,
+		base + "/location-area/b":                `{"pokemon_encounters":[{"pokemon":{"name":"pikachu"}},{"pokemon":{"name":"onix"},"version_details":[{"version":{"name":"diamond"}},{"version":{"name":"pearl"}}]}]}`,
+		base + "/location-area/c":                `{"pokemon_encounters":[]}`,
+		base + "/pokemon/pikachu":                `{"name":"pikachu","species":{"name":"pikachu"},"types":[{"type":{"name":"electric"}}],"moves":[` + pikachuMoves + `]}`,
+		base + "/pokemon/onix":                   `{"name":"onix","species":{"name":"onix"},"types":[{"type":{"name":"rock"}},{"type":{"name":"ground"}}]}`,
+		base + "/pokemon-species/pikachu":        `{"name":"pikachu","capture_rate":190,"evolution_chain":{"url":"` + base + `/evolution-chain/10/"}}`,
+		base + "/evolution-chain/10/":            `{"chain":{"species":{"name":"pichu"},"evolves_to":[{"species":{"name":"pikachu"},"evolves_to":[{"species":{"name":"raichu"},"evolution_details":[{"item":{"name":"thunder-stone"}}]}]}]}}`,
+		base + "/pokemon-species/pichu":          `{"name":"pichu","varieties":[{"is_default":true,"pokemon":{"name":"pichu"}}],"evolution_chain":{"url":"` + base + `/evolution-chain/10/"}}`,
+		base + "/pokemon-species/raichu":         `{"name":"raichu","varieties":[{"is_default":true,"pokemon":{"name":"raichu"}}],"evolution_chain":{"url":"` + base + `/evolution-chain/10/"}}`,
+		base + "/pokemon/pichu":                  `{"name":"pichu","species":{"name":"pichu"}}`,
+		base + "/pokemon/raichu":                 `{"name":"raichu","species":{"name":"raichu"}}`,
+		base + "/pokemon-species/onix":           `{"name":"onix","capture_rate":45}`,
+		base + "/version/diamond":                `{"name":"diamond","version_group":{"name":"diamond-pearl"}}`,
+		base + "/version/pearl":                  `{"name":"pearl","version_group":{"name":"diamond-pearl"}}`,
+		base + "/version-group/diamond-pearl":    `{"name":"diamond-pearl","generation":{"name":"generation-iv"}}`,
+		base + "/move/thunder-shock":             `{"name":"thunder-shock","power":40}`,
+		base + "/type/electric":                  `{"name":"electric"}`,
+		base + "/item/poke-ball":                 `{"name":"poke-ball"}`,
+		base + "/item/thunder-stone":             `{"name":"thunder-stone"}`,
+		base + "/type/rock":                      `{"name":"rock"}`,
+		base + "/type/ground":                    `{"name":"ground"}`,
+	}
+	fetched := make(map[string]int)
+	fetch := func(url string) ([]byte, error) {
+		fetched[url]++
+		if url == base+"/move/volt-tackle" || url == base+"/item/safari-ball" {
+			return nil, fmt.Errorf("%s: %w", url, pokeapi.ErrNotFound)
+		}
+		body, ok := responses[url]
+		if !ok {
+			return nil, fmt.Errorf("unexpected fetch: %s", url)
+		}
+		return []byte(body), nil
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot.zip")
+	w, err := Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Crawl(w, fetch, base, 0, []string{"poke-ball", "safari-ball"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, url := range []string{base + "/pokemon/pikachu", base + "/pokemon-species/pikachu", base + "/version-group/diamond-pearl", base + "/move/thunder-shock", base + "/type/electric", base + "/evolution-chain/10/", base + "/pokemon/raichu", base + "/item/thunder-stone"} {
+		if fetched[url] != 1 {
+			t.Errorf("%s fetched %d times", url, fetched[url])
+		}
+	}
+
+	archive, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+
+	client := &http.Client{Transport: archive}
+	res, err := client.Get(base + "/location-area/b?offset=0&limit=20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != responses[base+"/location-area/b"] {
+		t.Errorf("unexpected body: %s", body)
+	}
+
+	for _, url := range []string{base + "/move/thunder-shock", base + "/item/poke-ball", base + "/item/thunder-stone"} {
+		_, err = archive.Get(url)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	_, err = archive.Get(base + "/pokemon/mew")
+	if !errors.Is(err, ErrMissing) {
+		t.Errorf("expected ErrMissing, got %v", err)
+	}
+	// through the transport a missing resource is a 404 naming it
+	res, err = client.Get(base + "/pokemon/mew")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotFound || string(body) != "pokemon/mew is not in the offline snapshot" {
+		t.Errorf("expected a 404 for mew, got %d %s", res.StatusCode, body)
+	}
+}
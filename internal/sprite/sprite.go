@@ -0,0 +1,170 @@
+// Package sprite draws pokemon sprites in the terminal.
+package sprite
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+)
+
+// Mode is how a sprite is drawn
+type Mode string
+
+const (
+	// half blocks in 24-bit colour, two pixels to a character
+	TrueColor Mode = "truecolor"
+	// characters that get denser the darker the pixel is
+	ASCII Mode = "ascii"
+)
+
+// DefaultWidth fits a sprite next to a pokemon's details
+const DefaultWidth = 40
+
+// from lightest to darkest
+const ramp = ".:-=+*#%@"
+
+var ErrEmpty = errors.New("the sprite is empty")
+
+// Detect picks the mode for a terminal from its environment. anything that
+// isn't a terminal gets ASCII so the output can be saved or piped
+func Detect(getenv func(string) string, terminal bool) Mode {
+	if !terminal || getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" {
+		return ASCII
+	}
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+	return ASCII
+}
+
+func Decode(body []byte) (image.Image, error) {
+	img, err := png.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("the sprite isn't a png: %w", err)
+	}
+	return img, nil
+}
+
+// Render draws img at most width characters wide. the transparent border
+// sprites have is left out
+func Render(w io.Writer, img image.Image, mode Mode, width int) error {
+	bounds := opaqueBounds(img)
+	if bounds.Empty() {
+		return ErrEmpty
+	}
+	// a character is about twice as tall as it is wide, so each one
+	// covers a pixel of the grid across and two down
+	scale := max(1, float64(bounds.Dx())/float64(width))
+	cols := int(math.Ceil(float64(bounds.Dx()) / scale))
+	rows := int(math.Ceil(float64(bounds.Dy()) / scale))
+
+	out := bufio.NewWriter(w)
+	for y := 0; y < rows; y += 2 {
+		for x := 0; x < cols; x++ {
+			top := sample(img, bounds, scale, x, y)
+			bottom := color.NRGBA{}
+			if y+1 < rows {
+				bottom = sample(img, bounds, scale, x, y+1)
+			}
+			if mode == TrueColor {
+				out.WriteString(halfBlock(top, bottom))
+			} else {
+				out.WriteByte(character(top, bottom))
+			}
+		}
+		if mode == TrueColor {
+			out.WriteString("\x1b[0m")
+		}
+		out.WriteByte('\n')
+	}
+	return out.Flush()
+}
+
+// the smallest rectangle holding every pixel that isn't see-through
+func opaqueBounds(img image.Image) image.Rectangle {
+	b := img.Bounds()
+	opaque := image.Rectangle{}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a == 0 {
+				continue
+			}
+			opaque = opaque.Union(image.Rect(x, y, x+1, y+1))
+		}
+	}
+	return opaque
+}
+
+// sample averages the pixels under one cell of the scaled down grid,
+// weighing each colour by how opaque it is
+func sample(img image.Image, bounds image.Rectangle, scale float64, x, y int) color.NRGBA {
+	x0 := bounds.Min.X + int(float64(x)*scale)
+	y0 := bounds.Min.Y + int(float64(y)*scale)
+	x1 := min(bounds.Max.X, max(x0+1, bounds.Min.X+int(float64(x+1)*scale)))
+	y1 := min(bounds.Max.Y, max(y0+1, bounds.Min.Y+int(float64(y+1)*scale)))
+	var r, g, b, a, n uint64
+	for py := y0; py < y1; py++ {
+		for px := x0; px < x1; px++ {
+			pr, pg, pb, pa := img.At(px, py).RGBA()
+			// RGBA is premultiplied by alpha already
+			r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+			n++
+		}
+	}
+	if n == 0 || a == 0 {
+		return color.NRGBA{}
+	}
+	return color.NRGBA{
+		R: uint8(r * 0xff / a),
+		G: uint8(g * 0xff / a),
+		B: uint8(b * 0xff / a),
+		A: uint8((a / n) >> 8),
+	}
+}
+
+// mostly see-through cells are left blank
+func transparent(c color.NRGBA) bool {
+	return c.A < 0x80
+}
+
+func halfBlock(top, bottom color.NRGBA) string {
+	switch {
+	case transparent(top) && transparent(bottom):
+		return "\x1b[0m "
+	case transparent(bottom):
+		return "\x1b[0m" + foreground(top) + "▀"
+	case transparent(top):
+		return "\x1b[0m" + foreground(bottom) + "▄"
+	}
+	return foreground(top) + fmt.Sprintf("\x1b[48;2;%d;%d;%dm", bottom.R, bottom.G, bottom.B) + "▀"
+}
+
+func foreground(c color.NRGBA) string {
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", c.R, c.G, c.B)
+}
+
+func character(top, bottom color.NRGBA) byte {
+	var light float64
+	var n int
+	for _, c := range []color.NRGBA{top, bottom} {
+		if transparent(c) {
+			continue
+		}
+		// relative luminance, between 0 and 1
+		light += (0.2126*float64(c.R) + 0.7152*float64(c.G) + 0.0722*float64(c.B)) / 0xff
+		n++
+	}
+	if n == 0 {
+		return ' '
+	}
+	light /= float64(n)
+	return ramp[min(len(ramp)-1, int((1-light)*float64(len(ramp))))]
+}
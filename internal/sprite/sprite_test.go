@@ -0,0 +1,98 @@
+package sprite
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// a black pixel over a white one next to a black one, in a transparent border
+func testImage() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 6, 6))
+	img.Set(2, 2, color.NRGBA{A: 0xff})
+	img.Set(3, 2, color.NRGBA{A: 0xff})
+	img.Set(2, 3, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+	return img
+}
+
+func TestRender(t *testing.T) {
+	var buf bytes.Buffer
+	err := Render(&buf, testImage(), ASCII, DefaultWidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "+@\n" {
+		t.Errorf("unexpected ascii sprite: %q", buf.String())
+	}
+
+	buf.Reset()
+	err = Render(&buf, testImage(), TrueColor, DefaultWidth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "\x1b[38;2;0;0;0m\x1b[48;2;255;255;255m▀\x1b[0m\x1b[38;2;0;0;0m▀\x1b[0m\n"
+	if buf.String() != want {
+		t.Errorf("unexpected truecolor sprite: %q", buf.String())
+	}
+
+	// a 40 pixel wide sprite drawn 10 wide is a quarter of its size
+	wide := image.NewNRGBA(image.Rect(0, 0, 40, 8))
+	for x := 0; x < 40; x++ {
+		for y := 0; y < 8; y++ {
+			wide.Set(x, y, color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+		}
+	}
+	buf.Reset()
+	err = Render(&buf, wide, ASCII, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != ".........."+"\n" {
+		t.Errorf("unexpected scaled sprite: %q", buf.String())
+	}
+
+	err = Render(&buf, image.NewNRGBA(image.Rect(0, 0, 4, 4)), ASCII, DefaultWidth)
+	if !errors.Is(err, ErrEmpty) {
+		t.Errorf("expected ErrEmpty, got %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, testImage())
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := Decode(buf.Bytes())
+	if err != nil || img.Bounds().Dx() != 6 {
+		t.Errorf("unexpected decoded sprite: %v %v", img, err)
+	}
+	_, err = Decode([]byte("<html>"))
+	if err == nil || !strings.HasPrefix(err.Error(), "the sprite isn't a png") {
+		t.Errorf("expected a png error, got %v", err)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		env      map[string]string
+		terminal bool
+		want     Mode
+	}{
+		{map[string]string{"COLORTERM": "truecolor"}, true, TrueColor},
+		{map[string]string{"COLORTERM": "24bit"}, true, TrueColor},
+		{map[string]string{"COLORTERM": "truecolor"}, false, ASCII},
+		{map[string]string{"COLORTERM": "truecolor", "NO_COLOR": "1"}, true, ASCII},
+		{map[string]string{"TERM": "xterm"}, true, ASCII},
+	}
+	for _, c := range cases {
+		getenv := func(key string) string { return c.env[key] }
+		if got := Detect(getenv, c.terminal); got != c.want {
+			t.Errorf("%v terminal=%v: expected %s, got %s", c.env, c.terminal, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package typechart
+
+import (
+	"context"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"sort"
+	"sync"
+)
+
+// Chart is a type chart built from PokeAPI's /type damage relations.
+// types have to be loaded before they are looked up, anything that
+// hasn't been loaded counts as a neutral 1x matchup
+type Chart struct {
+	client *pokeapi.Client
+	mu     *sync.Mutex
+	// defending type -> attacking type -> multiplier
+	defense map[string]map[string]float64
+}
+
+func New(client *pokeapi.Client) *Chart {
+	return &Chart{
+		client:  client,
+		mu:      &sync.Mutex{},
+		defense: make(map[string]map[string]float64),
+	}
+}
+
+// Load fetches the damage relations of every type that isn't loaded yet
+func (c *Chart) Load(ctx context.Context, types ...string) error {
+	for _, name := range types {
+		c.mu.Lock()
+		_, ok := c.defense[name]
+		c.mu.Unlock()
+		if ok {
+			continue
+		}
+
+		t, err := c.client.Type(ctx, name)
+		if err != nil {
+			return err
+		}
+		from := make(map[string]float64)
+		for _, r := range t.DamageRelations.DoubleDamageFrom {
+			from[r.Name] = 2
+		}
+		for _, r := range t.DamageRelations.HalfDamageFrom {
+			from[r.Name] = 0.5
+		}
+		for _, r := range t.DamageRelations.NoDamageFrom {
+			from[r.Name] = 0
+		}
+
+		c.mu.Lock()
+		c.defense[name] = from
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// Multiplier is how much damage an attack of one type does to a pokemon
+// with the defending types, 4x and 0.25x for dual types
+func (c *Chart) Multiplier(attack string, defend ...string) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	multiplier := 1.0
+	for _, d := range defend {
+		if m, ok := c.defense[d][attack]; ok {
+			multiplier *= m
+		}
+	}
+	return multiplier
+}
+
+type Matchup struct {
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// Matchups lists every attacking type that isn't neutral against the
+// defending types, strongest first. the defending types must be loaded
+func (c *Chart) Matchups(defend ...string) []Matchup {
+	c.mu.Lock()
+	attacks := make(map[string]bool)
+	for _, d := range defend {
+		for attack := range c.defense[d] {
+			attacks[attack] = true
+		}
+	}
+	c.mu.Unlock()
+
+	var matchups []Matchup
+	for attack := range attacks {
+		m := c.Multiplier(attack, defend...)
+		if m != 1 {
+			matchups = append(matchups, Matchup{Type: attack, Multiplier: m})
+		}
+	}
+	sort.Slice(matchups, func(i, j int) bool {
+		if matchups[i].Multiplier != matchups[j].Multiplier {
+			return matchups[i].Multiplier > matchups[j].Multiplier
+		}
+		return matchups[i].Type < matchups[j].Type
+	})
+	return matchups
+}
+
+// Score rates a pokemon with the attacker types against one with the defender types.
+// offense is the best multiplier the attacker gets from a same type move,
+// defense is the worst multiplier the attacker takes from the defender's types.
+// all types must be loaded
+func (c *Chart) Score(attacker, defender []string) (offense, defense float64) {
+	for _, a := range attacker {
+		offense = max(offense, c.Multiplier(a, defender...))
+	}
+	for _, d := range defender {
+		defense = max(defense, c.Multiplier(d, attacker...))
+	}
+	return offense, defense
+}
@@ -0,0 +1,75 @@
+package typechart
+
+import (
+	"context"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi/pokeapitest"
+	"github.com/srijan-raghavula/pokedex/internal/pokecache"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestChart(t *testing.T) *Chart {
+	server := pokeapitest.NewServer(t)
+	client := pokeapi.NewClient(server.BaseURL, time.Second, pokecache.NewCache(time.Minute))
+	return New(client)
+}
+
+func TestMultiplier(t *testing.T) {
+	chart := newTestChart(t)
+	err := chart.Load(context.Background(), "water", "flying", "ground", "rock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		attack string
+		defend []string
+		want   float64
+	}{
+		{"electric", []string{"water", "flying"}, 4},
+		{"ground", []string{"water", "flying"}, 0},
+		{"fire", []string{"water", "flying"}, 0.5},
+		{"water", []string{"ground", "rock"}, 4},
+		{"normal", []string{"water"}, 1},
+		// not loaded, so neutral
+		{"fire", []string{"grass"}, 1},
+	}
+	for _, testCase := range cases {
+		got := chart.Multiplier(testCase.attack, testCase.defend...)
+		if got != testCase.want {
+			t.Errorf("%s vs %v: expected %v, got %v", testCase.attack, testCase.defend, testCase.want, got)
+		}
+	}
+}
+
+func TestMatchups(t *testing.T) {
+	chart := newTestChart(t)
+	err := chart.Load(context.Background(), "water", "flying")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Matchup{
+		{"electric", 4},
+		{"rock", 2},
+		{"bug", 0.5},
+		{"fighting", 0.5},
+		{"fire", 0.5},
+		{"steel", 0.5},
+		{"water", 0.5},
+		{"ground", 0},
+	}
+	got := chart.Matchups("water", "flying")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLoadMissingType(t *testing.T) {
+	chart := newTestChart(t)
+	err := chart.Load(context.Background(), "sound")
+	if err == nil {
+		t.Error("expected an error for a type that doesn't exist")
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// interrupter turns Ctrl-C into cancelling the command that's running.
+// pressing it again before anything else happens calls quit
+type interrupter struct {
+	mu sync.Mutex
+	// of the running command, nil between commands
+	cancel context.CancelFunc
+	// the last thing the user did was press Ctrl-C
+	pressed bool
+	quit    func()
+}
+
+func newInterrupter(quit func()) *interrupter {
+	return &interrupter{quit: quit}
+}
+
+// listen sends SIGINT to the interrupter until ctx is done
+func (i *interrupter) listen(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-signals:
+				i.interrupt()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// start gives a command a context that Ctrl-C cancels, done must be
+// called once the command has finished
+func (i *interrupter) start(parent context.Context) (ctx context.Context, done func()) {
+	ctx, cancel := context.WithCancel(parent)
+	i.mu.Lock()
+	i.cancel = cancel
+	i.mu.Unlock()
+	return ctx, func() {
+		i.mu.Lock()
+		i.cancel = nil
+		i.mu.Unlock()
+		cancel()
+	}
+}
+
+// interrupt is a press of Ctrl-C, whether it came as a signal or from the
+// line editor. it reports whether this was the first press in a row
+func (i *interrupter) interrupt() bool {
+	i.mu.Lock()
+	if i.pressed {
+		i.mu.Unlock()
+		i.quit()
+		return false
+	}
+	i.pressed = true
+	if i.cancel != nil {
+		i.cancel()
+	}
+	i.mu.Unlock()
+	return true
+}
+
+// reset is anything the user does other than pressing Ctrl-C
+func (i *interrupter) reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.pressed = false
+}
+
+// sleep waits for d unless ctx is done first. swapped out in tests so
+// catching doesn't take seconds
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
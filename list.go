@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// the most results list shows on a page
+const maxListLimit = 100
+
+// listResource is a PokeAPI list endpoint list can browse, by the name
+// the user types
+type listResource struct {
+	name     string
+	endpoint string
+}
+
+var listResources = []listResource{
+	{name: "pokemon", endpoint: "pokemon"},
+	{name: "moves", endpoint: "move"},
+	{name: "items", endpoint: "item"},
+	{name: "berries", endpoint: "berry"},
+	{name: "types", endpoint: "type"},
+	{name: "locations", endpoint: "location"},
+}
+
+// findListResource takes the plural like "moves" or the endpoint like "move"
+func findListResource(name string) (listResource, bool) {
+	for _, resource := range listResources {
+		if resource.name == name || resource.endpoint == name {
+			return resource, true
+		}
+	}
+	return listResource{}, false
+}
+
+func listResourceNames() []string {
+	names := make([]string, 0, len(listResources))
+	for _, resource := range listResources {
+		names = append(names, resource.name)
+	}
+	return names
+}
+
+// list shows a page of a resource. every resource keeps its own page, so
+// "list moves next" carries on where the last "list moves" left off
+func list(ctx context.Context, c *config, args ...string) (any, error) {
+	if len(args) < 4 {
+		return nil, errors.New("check the string passed into the function")
+	}
+	name, action, pageFlag, limitFlag := args[0], args[1], args[2], args[3]
+	resource, ok := findListResource(name)
+	if !ok {
+		return nil, fmt.Errorf("can't list %s (use %s)", name, strings.Join(listResourceNames(), ", "))
+	}
+	if c.cursors == nil {
+		c.cursors = make(map[string]*pokeapi.Cursor)
+	}
+	cur, ok := c.cursors[resource.endpoint]
+	if !ok {
+		cur = c.client.Cursor(resource.endpoint, pokeapi.DefaultLimit)
+		c.cursors[resource.endpoint] = cur
+	}
+	if limitFlag != "" {
+		limit, err := strconv.Atoi(limitFlag)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			return nil, usageError(fmt.Sprintf("--limit takes a number from 1 to %d", maxListLimit))
+		}
+		cur.SetLimit(limit)
+	}
+
+	var page pokeapi.ResourceList
+	var err error
+	switch {
+	case pageFlag != "" && action != "":
+		return nil, usageOf("list")
+	case pageFlag != "":
+		n, convErr := strconv.Atoi(pageFlag)
+		if convErr != nil {
+			return nil, usageOf("list")
+		}
+		page, err = cur.Goto(ctx, n)
+	case action == "":
+		page, err = cur.Current(ctx)
+	case action == "next":
+		page, err = cur.Next(ctx)
+	case action == "prev":
+		page, err = cur.Prev(ctx)
+	case action == "first":
+		page, err = cur.Goto(ctx, 1)
+	case action == "last":
+		page, err = cur.Last(ctx)
+	default:
+		return nil, usageOf("list")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := listResult{
+		Resource: resource.name,
+		Page:     cur.Page(),
+		Pages:    cur.Pages(),
+		Count:    cur.Count(),
+		Results:  []string{},
+	}
+	for _, result := range page.Results {
+		res.Results = append(res.Results, result.Name)
+	}
+	return res, nil
+}
+
+type listResult struct {
+	Resource string   `json:"resource"`
+	Page     int      `json:"page"`
+	Pages    int      `json:"pages"`
+	Count    int      `json:"count"`
+	Results  []string `json:"results"`
+}
+
+func (r listResult) Text(w io.Writer) error {
+	for _, name := range r.Results {
+		fmt.Fprintln(w, name)
+	}
+	_, err := fmt.Fprintf(w, "Page %d of %d (%d %s)\n", r.Page, r.Pages, r.Count, r.Resource)
+	return err
+}
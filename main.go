@@ -1,494 +1,553 @@
 package main
 
 import (
-	"bufio"
-	"encoding/json"
+	"context"
 	"errors"
+	"flag"
 	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/encounter"
+	"github.com/srijan-raghavula/pokedex/internal/fuzzy"
+	"github.com/srijan-raghavula/pokedex/internal/lineedit"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
 	"github.com/srijan-raghavula/pokedex/internal/pokecache"
 	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"github.com/srijan-raghavula/pokedex/internal/render"
+	"github.com/srijan-raghavula/pokedex/internal/search"
+	"github.com/srijan-raghavula/pokedex/internal/snapshot"
+	"github.com/srijan-raghavula/pokedex/internal/sprite"
+	"github.com/srijan-raghavula/pokedex/internal/typechart"
 	"io"
 	"log"
-	"net/http"
+	"math/rand"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
 
 func main() {
-	cfg := config{
-		next: "https://pokeapi.co/api/v2/location-area",
-		prev: "https://pokeapi.co/api/v2/location-area",
-	}
-	commands = map[string]command{
-		"help": {
-			name:        "help",
-			description: "prints a message to help yourself using Pokedex",
-			callback:    printCommands,
-		},
-		"exit": {
-			name:        "exit",
-			description: "exits Pokedex",
-			callback:    exit,
-		},
-		"map": {
-			name:        "map",
-			description: "prints the next 20 location areas in the pokemon world",
-			callback:    mapNext,
-		},
-		"mapb": {
-			name:        "mapb",
-			description: "prints the previous 20 location areas in the pokemon world (returns an error if you haven't started your exploration yet)",
-			callback:    mapPrev,
-		},
-		"explore": {
-			name:        "explore",
-			description: "takes a location area and lists all the Pokemons in the area",
-			callback:    pokemonList,
-		},
-		"catch": {
-			name:        "catch",
-			description: "catches pokemon and adds to Pokedex",
-			callback:    catchPokemon,
-		},
-		"inspect": {
-			name:        "inspect",
-			description: "inspects a Pokemon in your Pokedex and shows the details of the Pokemon",
-			callback:    inspectPokemon,
-		},
-		"pokedex": {
-			name:        "pokedex",
-			description: "lists all the Pokemons caught",
-			callback:    pokedex,
-		},
+	savePath, err := pokemon.DefaultSavePath()
+	if err != nil {
+		log.Fatal(err)
 	}
-	for {
-		scanned := bufio.NewScanner(os.Stdin)
-		fmt.Printf("Pokedex > ")
-		scanned.Scan()
-		stdIn, err := scanned.Text(), scanned.Err()
+	snapshotPath, err := snapshot.DefaultPath()
+	if err != nil {
+		log.Fatal(err)
+	}
+	indexPath, err := search.DefaultPath()
+	if err != nil {
+		log.Fatal(err)
+	}
+	offline := flag.Bool("offline", false, "serve every lookup from the local snapshot instead of PokeAPI")
+	flag.StringVar(&snapshotPath, "snapshot", snapshotPath, "path of the snapshot archive used by --offline and the snapshot command")
+	script := flag.String("script", "", "run the commands in a file (- for stdin) and exit")
+	jsonOutput := flag.Bool("json", false, "shorthand for --output json")
+	outputFlag := flag.String("output", string(render.Text), "how results are printed: text, json or table")
+	flag.Parse()
+	output, err := render.ParseFormat(*outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *jsonOutput {
+		output = render.JSON
+	}
+
+	client := pokeapi.NewClient(pokeapi.DefaultBaseURL, time.Second*10, newCache())
+	cfg := newConfig(client, time.Now().UnixNano(), output)
+	cfg.savePath = savePath
+	cfg.snapshotPath = snapshotPath
+	cfg.indexPath = indexPath
+	cfg.offline = *offline
+	loadSave(&cfg)
+	loadSettings(&cfg)
+	if cfg.offline {
+		archive, err := snapshot.Open(cfg.snapshotPath)
 		if err != nil {
-			fmt.Println(err)
+			log.Fatalf("offline mode needs a snapshot, run \"snapshot\" while online first: %v", err)
 		}
-
-		words := strings.Fields(stdIn)
-		for i, word := range words {
-			words[i] = strings.ToLower(word)
+		defer archive.Close()
+		cfg.client.SetTransport(archive)
+		fmt.Fprintf(os.Stderr, "Offline mode: using the snapshot at %s\n", cfg.snapshotPath)
+	}
+
+	commands = newCommands()
+	ctx := context.Background()
+	if *script != "" || flag.NArg() > 0 {
+		// the first Ctrl-C stops the command and with it the run, a second
+		// one gives up on the command stopping cleanly
+		interrupts := newInterrupter(func() { os.Exit(finish(&cfg, exitInterrupted)) })
+		interrupts.listen(ctx)
+		runCtx, done := interrupts.start(ctx)
+		code := 0
+		if *script != "" {
+			code = runScript(runCtx, &cfg, *script)
+		} else {
+			code = runArgs(runCtx, &cfg, flag.Args())
 		}
-		cmd := words[0]
-		noOfWords := len(words)
+		done()
+		os.Exit(code)
+	}
 
-		switch cmd {
-		case "help":
-			err := commands[cmd].callback(&cfg)
-			if err != nil {
-				log.Fatal(err)
-			}
-		case "exit":
-			err := commands[cmd].callback(&cfg)
-			if err != nil {
-				log.Fatal(err)
-			}
-		case "map":
-			err := commands[cmd].callback(&cfg)
-			if err != nil {
-				log.Fatal(err)
-			}
-		case "mapb":
-			err := commands[cmd].callback(&cfg)
-			if err != nil {
-				fmt.Println(err)
-			}
-		case "explore":
-			if noOfWords < 2 {
-				fmt.Println("usage: explore <location-area-name>")
-				break
-			}
-			locationAreaEndpoint := (words[1])
-			err := commands[cmd].callback(&cfg, locationAreaEndpoint)
-			if err != nil {
-				fmt.Println(err)
-			}
-		case "catch":
-			if noOfWords < 2 {
-				fmt.Println("usage: catch <pokemon-name>")
-				break
-			}
-			pokemonName := strings.ToLower(words[1])
-			err := commands[cmd].callback(&cfg, pokemonName)
-			if err != nil {
-				fmt.Println(err)
-			}
-		case "inspect":
-			if noOfWords < 2 {
-				fmt.Println("usage: catch <pokemon-name>")
-				break
-			}
-			pokemonName := strings.ToLower(words[1])
-			err := commands[cmd].callback(&cfg, pokemonName)
-			if err != nil {
-				fmt.Println(err)
-			}
-		case "pokedex":
-			err := commands[cmd].callback(&cfg)
-			if err != nil {
-				fmt.Println(err)
-			}
-		default:
-			fmt.Println(`Available commands to use(use "help" for more details)`)
-			for k := range commands {
-				fmt.Println(k)
+	interrupts := newInterrupter(func() {
+		fmt.Println()
+		_, err := exit(ctx, &cfg)
+		printError(os.Stdout, &cfg, err, "")
+		os.Exit(exitFailed)
+	})
+	interrupts.listen(ctx)
+
+	editor := lineedit.New(os.Stdin, os.Stdout, historyPath(cfg.savePath))
+	editor.Complete = completer(&cfg)
+	for {
+		stdIn, err := editor.ReadLine("Pokedex > ")
+		if errors.Is(err, io.EOF) {
+			// end of input, leave the same way exit does
+			fmt.Println()
+			_, err = exit(ctx, &cfg)
+			// there's no more input to keep the session going with
+			printError(os.Stdout, &cfg, err, "")
+			os.Exit(exitFailed)
+		}
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			if interrupts.interrupt() {
+				fmt.Println("(press Ctrl-C again to save and exit)")
 			}
+			continue
+		}
+		interrupts.reset()
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		err = editor.AddHistory(stdIn)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not save your history: %v\n", err)
 		}
 
+		commandCtx, done := interrupts.start(ctx)
+		err = runCommand(commandCtx, &cfg, stdIn)
+		done()
+		if err != nil {
+			printError(os.Stdout, &cfg, err, "")
+		}
 	}
 }
 
-type command struct {
-	name        string
-	description string
-	callback    func(*config, ...string) error
-}
-
 type config struct {
-	next string
-	prev string
+	client  *pokeapi.Client
+	types   *typechart.Chart
+	catcher *pokemon.Catcher
+	// wild encounters roll with this
+	rng *rand.Rand
+	// the location area the player is in, empty until the first goto
+	location string
+	// the last pokemon found by walk, catch goes after it without searching
+	encounter *encounter.Wild
+	// the version of the games lookups are narrowed to, set with version
+	game pokemon.Game
+	// the location areas of the last map page, for tab completion
+	areas []string
+	// where list is in each resource, by endpoint
+	cursors map[string]*pokeapi.Cursor
+	// the location area pages map goes through
+	areaPages *pokeapi.Cursor
+	// every page map has shown, the one on show last. mapb goes back
+	// through them
+	mapHistory   []int
+	savePath     string
+	snapshotPath string
+	// where the search index is kept between sessions
+	indexPath string
+	// loaded the first time a search or a misspelled name needs it
+	index   *search.Index
+	offline bool
+	// how inspect --sprite draws, ascii unless the terminal has colours
+	spriteMode sprite.Mode
+	// output is the format for the command being run, from its
+	// --output flag or outputDefault
+	output        render.Format
+	outputDefault render.Format
 }
 
-type locList struct {
-	Count    int    `json:"count"`
-	Next     string `json:"next"`
-	Previous string `json:"previous"`
-	Results  []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"results"`
+// newConfig is everything a session needs that doesn't depend on where
+// files are kept, catches and wild encounters roll from seed
+func newConfig(client *pokeapi.Client, seed int64, output render.Format) config {
+	return config{
+		client:        client,
+		types:         typechart.New(client),
+		catcher:       pokemon.NewCatcher(pokemon.CaptureFormula{}, rand.NewSource(seed)),
+		rng:           rand.New(rand.NewSource(seed)),
+		areaPages:     client.Cursor("location-area", pokeapi.DefaultLimit),
+		output:        output,
+		outputDefault: output,
+		spriteMode:    spriteMode(),
+	}
 }
 
-var commands map[string]command
-var isFirstCall bool = true
-var cache pokecache.Cache = pokecache.NewCache(time.Minute * 2)
-
-func printCommands(c *config, s ...string) error {
-	if len(commands) == 0 {
-		return errors.New("no commands yet")
-	}
-	fmt.Println("=======Pokedex help center=======")
-	fmt.Println("\nusage: [command]\n\nCOMMANDS TO USE:")
-	for k, v := range commands {
-		fmt.Printf("%v\n", k)
-		if v.description == "" {
-			return errors.New("command not described")
-		}
-		fmt.Printf("%v\n\n", v.description)
+func newCache() pokecache.Cache {
+	const interval = time.Minute * 2
+	dir, err := pokecache.DefaultDiskDir()
+	if err != nil {
+		return pokecache.NewCache(interval)
 	}
-	return nil
+	// a day of responses, capped at 64MB
+	disk, err := pokecache.NewDiskCache(dir, time.Hour*24, 64<<20)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "disk cache disabled: %v\n", err)
+		return pokecache.NewCache(interval)
+	}
+	return pokecache.NewCacheWithDisk(interval, disk)
 }
 
-func exit(c *config, s ...string) error {
-	os.Exit(1)
-	return nil
+func exit(ctx context.Context, c *config, s ...string) (any, error) {
+	err := pokemon.Pokemons.Save(c.savePath)
+	if err != nil {
+		return nil, err
+	}
+	os.Exit(0)
+	return nil, nil
 }
 
-func mapNext(c *config, s ...string) error {
-	val, ok := cache.Get(c.next)
-	if ok {
-		var unmarshaled locList
-		err := json.Unmarshal(val, &unmarshaled)
-		if err != nil {
-			return err
-		}
+func save(ctx context.Context, c *config, s ...string) (any, error) {
+	err := pokemon.Pokemons.Save(c.savePath)
+	if err != nil {
+		return nil, err
+	}
+	return saveResult{Path: c.savePath}, nil
+}
 
-		for _, result := range unmarshaled.Results {
-			fmt.Println(result.Name)
-		}
-		c.prev = unmarshaled.Previous
-		c.next = unmarshaled.Next
-		return nil
+func load(ctx context.Context, c *config, files ...string) (any, error) {
+	if len(files) < 1 {
+		return nil, errors.New("check the string passed into the function")
 	}
-	res, err := http.Get(c.next)
+	err := pokemon.Pokemons.Load(files[0])
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return loadResult{Path: files[0]}, nil
+}
 
-	if sc := res.StatusCode; sc > 299 {
-		errorMessage := "response status code: " + fmt.Sprintf("%d", sc)
-		return errors.New(errorMessage)
+func takeSnapshot(ctx context.Context, c *config, args ...string) (any, error) {
+	if c.offline {
+		return nil, errors.New("can't take a snapshot in offline mode")
+	}
+	maxAreas := 0
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return nil, usageOf("snapshot")
+		}
+		maxAreas = n
 	}
 
-	body, err := io.ReadAll(res.Body)
+	w, err := snapshot.Create(c.snapshotPath)
+	if err != nil {
+		return nil, err
+	}
+	fetch := func(url string) ([]byte, error) {
+		return c.client.Fetch(ctx, url)
+	}
+	areas := 0
+	err = snapshot.Crawl(w, fetch, c.client.BaseURL(), maxAreas, bagItems(), func(done, total int) {
+		areas = done
+		// progress goes to stderr so it never ends up in json output
+		fmt.Fprintf(os.Stderr, "\rdownloaded %d/%d location areas", done, total)
+	})
+	fmt.Fprintln(os.Stderr)
 	if err != nil {
-		return err
+		w.Abort()
+		return nil, err
 	}
-	cache.Add(c.next, body)
-	res.Body.Close()
-
-	var unmarshaled locList
-	err = json.Unmarshal(body, &unmarshaled)
+	err = w.Close()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return snapshotResult{Path: c.snapshotPath, LocationAreas: areas}, nil
+}
 
-	for _, result := range unmarshaled.Results {
-		fmt.Println(result.Name)
+// loads the save from the previous session, if there is one.
+// a save that can't be loaded is moved aside instead of being overwritten on exit
+func loadSave(c *config) {
+	err := pokemon.Pokemons.Load(c.savePath)
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		return
 	}
-	if !isFirstCall {
-		c.prev = unmarshaled.Previous
+	backup := c.savePath + ".bak"
+	renameErr := os.Rename(c.savePath, backup)
+	if renameErr != nil {
+		fmt.Fprintf(os.Stderr, "could not load your Pokedex: %v\n", err)
+		return
 	}
-	c.next = unmarshaled.Next
-	isFirstCall = false
-	return nil
+	fmt.Fprintf(os.Stderr, "could not load your Pokedex (%v), moved it to %s and started a new one\n", err, backup)
 }
 
-func mapPrev(c *config, s ...string) error {
-	val, ok := cache.Get(c.prev)
-	if ok {
-		var unmarshaled locList
-		err := json.Unmarshal(val, &unmarshaled)
-		if err != nil {
-			return err
-		}
-
-		for _, result := range unmarshaled.Results {
-			fmt.Println(result.Name)
+// mapNext shows the next page of location areas, or the first, the last or
+// any page, and remembers it for mapb
+func mapNext(ctx context.Context, c *config, args ...string) (any, error) {
+	action, pageArg := "", ""
+	if len(args) > 0 {
+		action = args[0]
+	}
+	if len(args) > 1 {
+		pageArg = args[1]
+	}
+	var locations pokeapi.ResourceList
+	var err error
+	switch {
+	case action == "" && pageArg == "":
+		locations, err = c.areaPages.Next(ctx)
+	case action == "first" && pageArg == "":
+		locations, err = c.areaPages.Goto(ctx, 1)
+	case action == "last" && pageArg == "":
+		locations, err = c.areaPages.Last(ctx)
+	case action == "goto" && pageArg != "":
+		page, convErr := strconv.Atoi(pageArg)
+		if convErr != nil {
+			return nil, usageOf("map")
 		}
-		return nil
+		locations, err = c.areaPages.Goto(ctx, page)
+	default:
+		return nil, usageOf("map")
 	}
-	if isFirstCall {
-		return errors.New("no prev locations to show")
+	if errors.Is(err, pokeapi.ErrLastPage) {
+		return nil, errors.New("no next locations to show, this is the last page")
 	}
-	res, err := http.Get(c.prev)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if sc := res.StatusCode; sc > 299 {
-		errorMessage := "response status code:" + fmt.Sprintf("%d", sc)
-		return errors.New(errorMessage)
+	page := c.areaPages.Page()
+	// showing the same page again isn't somewhere new to go back from
+	if len(c.mapHistory) == 0 || c.mapHistory[len(c.mapHistory)-1] != page {
+		c.mapHistory = append(c.mapHistory, page)
 	}
+	res := newLocationsResult(locations)
+	c.areas = res.LocationAreas
+	return res, nil
+}
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return err
+// mapPrev goes back to the page map showed before the current one
+func mapPrev(ctx context.Context, c *config, s ...string) (any, error) {
+	if len(c.mapHistory) < 2 {
+		return nil, errors.New("no prev locations to show")
 	}
-	cache.Add(c.prev, body)
-	res.Body.Close()
-
-	var unmarshaled locList
-	err = json.Unmarshal(body, &unmarshaled)
+	locations, err := c.areaPages.Goto(ctx, c.mapHistory[len(c.mapHistory)-2])
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, result := range unmarshaled.Results {
-		fmt.Println(result.Name)
-	}
-	return nil
+	c.mapHistory = c.mapHistory[:len(c.mapHistory)-1]
+	res := newLocationsResult(locations)
+	c.areas = res.LocationAreas
+	return res, nil
 }
 
-func pokemonList(c *config, names ...string) error {
+func pokemonList(ctx context.Context, c *config, names ...string) (any, error) {
 	if len(names) < 1 {
-		return errors.New("check the string passed into the function")
+		return nil, errors.New("check the string passed into the function")
 	}
-	endpoint := names[0]
-	unmarshaled, err := pokemonsInArea(endpoint, c.next, c.prev)
-	if err != nil {
-		return err
+	area, err := c.client.LocationArea(ctx, names[0])
+	if errors.Is(err, pokeapi.ErrNotFound) && !snapshot.IsMissing(err) {
+		return nil, unknownAreaError(ctx, c, names[0])
 	}
-	for _, pokemon := range unmarshaled.PokemonEncounters {
-		fmt.Println(pokemon.Pokemon.Name)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	res := exploreResult{LocationArea: area.Name, Pokemon: []string{}}
+	res.Pokemon = append(res.Pokemon, encounter.Pokemon(area, c.game.Version)...)
+	return res, nil
 }
 
-func pokemonsInArea(endpoint, next, prev string) (locEndpoint, error) {
-	val, ok := cache.Get(endpoint)
-	var unmarshaled locEndpoint
-	if ok {
-		var unmarshaled locEndpoint
-		err := json.Unmarshal(val, &unmarshaled)
+func catchPokemon(ctx context.Context, c *config, args ...string) (any, error) {
+	if len(args) < 4 {
+		return nil, errors.New("check the string passed into the function")
+	}
+	name, nickname, ball, status := args[0], args[1], args[2], args[3]
+	if nickname != "" {
+		err := pokemon.ValidNickname(nickname)
 		if err != nil {
-			return unmarshaled, err
+			return nil, err
 		}
-
-		return unmarshaled, nil
 	}
-
-	// players most likely will explore an area
-	// they know the name of and they know the name
-	// from using map previously, if they used
-	// map before, config.prev will have the appropriate url
-	// if they magically just knew the area name from next,
-	// we will try that url in an if case
-	var url string
-	querySeparated := strings.Split(prev, "?")
-	if len(querySeparated) == 1 {
-		url = fmt.Sprintf("%s/%s", prev, endpoint)
-	} else {
-		url = fmt.Sprintf("%s/%s?%s", querySeparated[0], endpoint, querySeparated[1])
+	if ball == "" {
+		ball = pokemon.DefaultBall
 	}
-
-	res, err := http.Get(url)
+	err := pokemon.CheckBall(ball)
 	if err != nil {
-		return unmarshaled, err
+		return nil, err
 	}
-	if sc := res.StatusCode; sc > 299 {
-		if sc == 404 {
-			return unmarshaled, errors.New("invalid location-area-name (possible spelling mistakes)")
-		}
-		errorMessage := "response status code: " + fmt.Sprintf("%d", sc)
-		return unmarshaled, errors.New(errorMessage)
-	}
-
-	body, err := io.ReadAll(res.Body)
+	err = pokemon.CheckStatus(status)
 	if err != nil {
-		return unmarshaled, err
+		return nil, err
+	}
+	if c.location == "" {
+		return nil, errors.New("You need to be somewhere to catch Pokemons, use goto <location-area-name> first")
 	}
 
-	err = json.Unmarshal(body, &unmarshaled)
+	area, err := c.client.LocationArea(ctx, c.location)
 	if err != nil {
-		return unmarshaled, err
+		return nil, err
 	}
+	var wild encounter.Wild
+	appeared := true
+	if c.encounter != nil && c.encounter.Pokemon == name {
+		wild = *c.encounter
+	} else {
+		wild, appeared, err = encounter.Look(area, name, c.game.Version, c.rng)
+	}
+	if errors.Is(err, encounter.ErrNotHere) {
+		return nil, notHereError(c, area, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	res := catchResult{
+		Pokemon:  name,
+		Location: area.Name,
+		Appeared: appeared,
+		Method:   wild.Method,
+		Level:    wild.Level,
+	}
+	if !appeared {
+		return res, nil
+	}
+
+	outcome, err := c.catcher.Catch(ctx, c.client, name, pokemon.Throw{
+		Ball:     ball,
+		Status:   status,
+		Level:    wild.Level,
+		Location: area.Name,
+		Nickname: nickname,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// caught or not, it's gone after a throw
+	c.encounter = nil
+	if c.output == render.Text {
+		catchAnimation(ctx, name)
+	}
+	res.Ball = outcome.Ball
+	res.Probability = outcome.Probability
+	res.Caught = outcome.Caught
+	if outcome.Caught {
+		caught := outcome.Instance
+		res.ID = caught.ID
+		res.Nickname = caught.Nickname
+		res.InParty = slices.ContainsFunc(pokemon.Pokemons.PartyMembers(), func(p pokemon.Caught) bool {
+			return p.ID == caught.ID
+		})
+	}
+	return res, nil
+}
 
-	if len(unmarshaled.PokemonEncounters) == 0 {
-		var url string
-		querySeparated := strings.Split(prev, "?")
-		if len(querySeparated) == 1 {
-			url = fmt.Sprintf("%s/%s", next, endpoint)
-		} else {
-			url = fmt.Sprintf("%s/%s?%s", querySeparated[0], endpoint, querySeparated[1])
-		}
-		res, err := http.Get(url)
-		if err != nil {
-			return unmarshaled, err
-		}
-		if sc := res.StatusCode; sc > 299 {
-			if sc == 404 {
-				return unmarshaled, errors.New("invalid location-area-name (possible spelling mistakes)")
-			}
-			errorMessage := "response status code: " + fmt.Sprintf("%d", sc)
-			return unmarshaled, errors.New(errorMessage)
-		}
+// notHereError suggests the pokemon of the area spelled like the one asked for
+func notHereError(c *config, area pokeapi.LocationArea, name string) error {
+	where := area.Name
+	if c.game.Version != "" {
+		where += " in " + c.game.Version
+	}
+	suggestions := fuzzy.Suggest(name, encounter.Pokemon(area, c.game.Version), fuzzy.Threshold(name))
+	if len(suggestions) == 0 {
+		return fmt.Errorf("There are no %s in %s", name, where)
+	}
+	return fmt.Errorf("There are no %s in %s, did you mean %s?", name, where, strings.Join(suggestions, " or "))
+}
 
-		body, err := io.ReadAll(res.Body)
-		if err != nil {
-			return unmarshaled, err
-		}
-		cache.Add(endpoint, body)
-		res.Body.Close()
+func goTo(ctx context.Context, c *config, names ...string) (any, error) {
+	if len(names) < 1 {
+		return nil, errors.New("check the string passed into the function")
+	}
+	area, err := c.client.LocationArea(ctx, names[0])
+	if errors.Is(err, pokeapi.ErrNotFound) && !snapshot.IsMissing(err) {
+		return nil, unknownAreaError(ctx, c, names[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.location = area.Name
+	c.encounter = nil
+	res := gotoResult{LocationArea: area.Name, Pokemon: []string{}}
+	res.Pokemon = append(res.Pokemon, encounter.Pokemon(area, c.game.Version)...)
+	return res, nil
+}
 
-		var unmarshaled locEndpoint
-		err = json.Unmarshal(body, &unmarshaled)
-		if err != nil {
-			return unmarshaled, err
+// the ball has been thrown already, Ctrl-C only skips to the outcome
+func catchAnimation(ctx context.Context, name string) {
+	fmt.Printf("⠀⠀⠀⠀⠀⠀⠀⠀⢀⣠⣤⣶⣶⣿⣿⣿⣿⣿⣶⣶⣤⣄⡀⠀⠀⠀⠀⠀⠀⠀\n⠀⠀⠀⠀⠀⠀⣠⣶⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣶⣄⠀⠀⠀⠀⠀\n⠀⠀⠀⠀⣠⣾⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣷⡄⠀⠀⠀\n⠀⠀⠀⣼⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⡏⠀⠀⠙⣿⣿⣿⣿⣿⣆⠀⠀\n⠀⠀⣼⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⡿⠿⠿⢿⣧⡀⠀⢠⣿⠟⠛⠛⠿⣿⡆⠀\n⠀⢰⣿⣿⣿⣿⣿⣿⠿⠟⠋⠉⠁⠀⠀⠀⠀⠀⠙⠿⠿⠟⠋⠀⠀⠀⣠⣿⠇⠀\n⠀⢸⣿⣿⡿⠟⠉⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⣀⣤⣾⠟⠋⠀⠀\n⠀⢸⣿⠋⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⣀⣀⣤⣴⣾⠿⠛⠉⠀⠀⠀⠀⠀\n⠀⠈⢿⣷⣤⣤⣄⣠⣤⣤⣤⣤⣶⣶⣾⠿⠿⠛⠛⠉⠁⠀⠀⠀⠀⠀⠀⠀⠀⠀\n⠀⢠⣾⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣷⣶⣦⣤⣀⠀⠀⠀⠀⠀⠀⠀⠀\n⠀⢸⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣷⣦⣄⠀⠀⠀⠀\n⠀⢸⣿⡛⠿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣦⡀⠀\n⠀⠀⢻⣧⠀⠈⠙⠛⠿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⡇⠀\n⠀⠀⠈⢿⣧⠀⠀⠀⠀⠀⠀⠉⠙⠛⠻⠿⠿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⡿⠁⠀\n⠀⠀⠀⠀⠻⣷⣄⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠹⣿⣿⣿⣿⠟⠀⣠⣾⠟⠀⠀⠀\n⠀⠀⠀⠀⠀⠈⠻⣷⣦⣀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠉⠉⢀⣤⣾⠟⠁⠀⠀⠀⠀\n⠀⠀⠀⠀⠀⠀⠀⠀⠙⠻⠿⣶⣦⣤⣤⣤⣤⣤⣤⣶⡿⠟⠋⠁⠀⠀⠀⠀⠀⠀\n⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠈⠉⠉⠉⠉⠉⠉⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀\n\n\n")
+	steps := []struct {
+		wait time.Duration
+		text string
+	}{
+		{time.Second, "Catching " + name + " "},
+		{time.Millisecond * 750, ". "},
+		{time.Millisecond * 750, ". "},
+		{time.Millisecond * 750, "."},
+		{time.Second, ""},
+	}
+	for _, step := range steps {
+		if sleep(ctx, step.wait) != nil {
+			break
 		}
-
-		return unmarshaled, nil
+		fmt.Print(step.text)
 	}
-
-	cache.Add(endpoint, body)
-	res.Body.Close()
-
-	return unmarshaled, nil
+	fmt.Println()
 }
 
-func catchPokemon(c *config, name ...string) error {
-	if len(name) < 1 {
-		return errors.New("check the string passed into the function")
+func inspectPokemon(ctx context.Context, c *config, args ...string) (any, error) {
+	if len(args) < 2 {
+		return nil, errors.New("check the string passed into the function")
 	}
-	isCaught, err := pokemon.IsCaught(name[0])
+	p, err := pokemon.Pokemons.Get(args[0])
 	if err != nil {
-		return err
+		return nil, err
 	}
-	fmt.Printf("⠀⠀⠀⠀⠀⠀⠀⠀⢀⣠⣤⣶⣶⣿⣿⣿⣿⣿⣶⣶⣤⣄⡀⠀⠀⠀⠀⠀⠀⠀\n⠀⠀⠀⠀⠀⠀⣠⣶⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣶⣄⠀⠀⠀⠀⠀\n⠀⠀⠀⠀⣠⣾⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣷⡄⠀⠀⠀\n⠀⠀⠀⣼⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⡏⠀⠀⠙⣿⣿⣿⣿⣿⣆⠀⠀\n⠀⠀⣼⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⡿⠿⠿⢿⣧⡀⠀⢠⣿⠟⠛⠛⠿⣿⡆⠀\n⠀⢰⣿⣿⣿⣿⣿⣿⠿⠟⠋⠉⠁⠀⠀⠀⠀⠀⠙⠿⠿⠟⠋⠀⠀⠀⣠⣿⠇⠀\n⠀⢸⣿⣿⡿⠟⠉⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⣀⣤⣾⠟⠋⠀⠀\n⠀⢸⣿⠋⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⣀⣀⣤⣴⣾⠿⠛⠉⠀⠀⠀⠀⠀\n⠀⠈⢿⣷⣤⣤⣄⣠⣤⣤⣤⣤⣶⣶⣾⠿⠿⠛⠛⠉⠁⠀⠀⠀⠀⠀⠀⠀⠀⠀\n⠀⢠⣾⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣷⣶⣦⣤⣀⠀⠀⠀⠀⠀⠀⠀⠀\n⠀⢸⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣷⣦⣄⠀⠀⠀⠀\n⠀⢸⣿⡛⠿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣦⡀⠀\n⠀⠀⢻⣧⠀⠈⠙⠛⠿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⡇⠀\n⠀⠀⠈⢿⣧⠀⠀⠀⠀⠀⠀⠉⠙⠛⠻⠿⠿⣿⣿⣿⣿⣿⣿⣿⣿⣿⣿⡿⠁⠀\n⠀⠀⠀⠀⠻⣷⣄⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠹⣿⣿⣿⣿⠟⠀⣠⣾⠟⠀⠀⠀\n⠀⠀⠀⠀⠀⠈⠻⣷⣦⣀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠉⠉⢀⣤⣾⠟⠁⠀⠀⠀⠀\n⠀⠀⠀⠀⠀⠀⠀⠀⠙⠻⠿⣶⣦⣤⣤⣤⣤⣤⣤⣶⡿⠟⠋⠁⠀⠀⠀⠀⠀⠀\n⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠈⠉⠉⠉⠉⠉⠉⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀⠀\n\n\n")
-	time.Sleep(time.Second * 1)
-	fmt.Printf("Catching %s ", name[0])
-	time.Sleep(time.Millisecond * 750)
-	fmt.Printf(". ")
-	time.Sleep(time.Millisecond * 750)
-	fmt.Printf(". ")
-	time.Sleep(time.Millisecond * 750)
-	fmt.Printf(".\n")
-	time.Sleep(time.Second * 1)
-	if isCaught {
-		fmt.Printf("%s was caught and added to your Pokedex\n", name[0])
-		return nil
-	}
-	fmt.Printf("%s managed to not get caught\n", name[0])
-	return nil
+	res := newInspectResult(p, c.game)
+	if args[1] == "" {
+		return res, nil
+	}
+	res.Sprite, err = c.game.SpriteVariant(p, args[1])
+	if err != nil {
+		return nil, err
+	}
+	// only text output has room for the picture, json gets the url
+	if c.output != render.Text {
+		return res, nil
+	}
+	res.Art, err = drawSprite(ctx, c, res.Sprite)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
-func inspectPokemon(c *config, name ...string) error {
-	pokemon, err := pokemon.Pokemons.Get(name[0])
+// drawSprite downloads a sprite, through the cache like any other lookup,
+// and draws it the way the terminal can show it
+func drawSprite(ctx context.Context, c *config, url string) (string, error) {
+	body, err := c.client.Get(ctx, url)
 	if err != nil {
-		return err
+		return "", err
 	}
-	fmt.Printf("Pokemon: %s\n", pokemon.Name)
-	fmt.Printf("Height: %d | Weight: %d\n", pokemon.Height, pokemon.Weight)
-	fmt.Println("==TYPES==")
-	for _, pokemonType := range pokemon.Types {
-		fmt.Println(pokemonType.Type.Name)
+	img, err := sprite.Decode(body)
+	if err != nil {
+		return "", err
 	}
-	fmt.Println("==STATS==")
-	for _, stat := range pokemon.Stats {
-		fmt.Printf("%s: %d\n", stat.Stat.Name, stat.BaseStat)
+	var art strings.Builder
+	err = sprite.Render(&art, img, c.spriteMode, sprite.DefaultWidth)
+	if err != nil {
+		return "", err
 	}
-	return err
+	return art.String(), nil
 }
 
-func pokedex(c *config, name ...string) error {
-	return pokemon.Pokemons.Print()
+// spriteMode is how sprites are drawn on stdout
+func spriteMode() sprite.Mode {
+	info, err := os.Stdout.Stat()
+	terminal := err == nil && info.Mode()&os.ModeCharDevice != 0
+	return sprite.Detect(os.Getenv, terminal)
 }
 
-type locEndpoint struct {
-	EncounterMethodRates []struct {
-		EncounterMethod struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"encounter_method"`
-		VersionDetails []struct {
-			Rate    int `json:"rate"`
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
-		} `json:"version_details"`
-	} `json:"encounter_method_rates"`
-	GameIndex int `json:"game_index"`
-	ID        int `json:"id"`
-	Location  struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"location"`
-	Name  string `json:"name"`
-	Names []struct {
-		Language struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-		Name string `json:"name"`
-	} `json:"names"`
-	PokemonEncounters []struct {
-		Pokemon struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"pokemon"`
-		VersionDetails []struct {
-			EncounterDetails []struct {
-				Chance          int   `json:"chance"`
-				ConditionValues []any `json:"condition_values"`
-				MaxLevel        int   `json:"max_level"`
-				Method          struct {
-					Name string `json:"name"`
-					URL  string `json:"url"`
-				} `json:"method"`
-				MinLevel int `json:"min_level"`
-			} `json:"encounter_details"`
-			MaxChance int `json:"max_chance"`
-			Version   struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
-		} `json:"version_details"`
-	} `json:"pokemon_encounters"`
+func pokedex(ctx context.Context, c *config, name ...string) (any, error) {
+	return pokedexResult{Pokemon: pokemon.Pokemons.Names()}, nil
 }
@@ -0,0 +1,1283 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi/pokeapitest"
+	"github.com/srijan-raghavula/pokedex/internal/pokecache"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"github.com/srijan-raghavula/pokedex/internal/render"
+	"github.com/srijan-raghavula/pokedex/internal/snapshot"
+	"github.com/srijan-raghavula/pokedex/internal/typechart"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestConfig(t *testing.T) *config {
+	t.Helper()
+	server := pokeapitest.NewServer(t)
+	client := pokeapi.NewClient(server.BaseURL, time.Second, pokecache.NewCache(time.Minute))
+	client.SetRetryPolicy(pokeapi.RetryPolicy{MaxAttempts: 1})
+
+	commands = newCommands()
+	sleep = func(context.Context, time.Duration) error { return nil }
+	pokemon.Pokemons.Reset()
+
+	c := newConfig(client, 1, render.Text)
+	c.savePath = filepath.Join(t.TempDir(), "save.json")
+	c.indexPath = filepath.Join(t.TempDir(), "search.json")
+	return &c
+}
+
+// runs a line like the REPL does and returns everything it printed
+func run(t *testing.T, c *config, line string) string {
+	t.Helper()
+	return capture(t, func() {
+		err := runCommand(context.Background(), c, line)
+		if err != nil {
+			fmt.Println(err)
+		}
+	})
+}
+
+func capture(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		output <- string(b)
+	}()
+
+	f()
+
+	os.Stdout = stdout
+	w.Close()
+	return <-output
+}
+
+// newOfflineConfig crawls areas of the fake PokeAPI into a snapshot and
+// returns a session that only has that snapshot to go on, like --offline
+func newOfflineConfig(t *testing.T, areas int) *config {
+	t.Helper()
+	online := newTestConfig(t)
+	online.snapshotPath = filepath.Join(t.TempDir(), "snapshot.zip")
+	capture(t, func() {
+		_, err := takeSnapshot(context.Background(), online, strconv.Itoa(areas))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	archive, err := snapshot.Open(online.snapshotPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { archive.Close() })
+
+	client := pokeapi.NewClient(online.client.BaseURL(), time.Second, pokecache.NewCache(time.Minute))
+	client.SetRetryPolicy(pokeapi.RetryPolicy{MaxAttempts: 1})
+	client.SetTransport(archive)
+	c := newConfig(client, 1, render.Text)
+	c.savePath = online.savePath
+	c.indexPath = online.indexPath
+	c.snapshotPath = online.snapshotPath
+	c.offline = true
+	return &c
+}
+
+func fetch(t *testing.T, c *config, name string) []byte {
+	t.Helper()
+	body, err := c.client.Get(context.Background(), c.client.URL("pokemon", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func addPokemon(t *testing.T, body []byte) {
+	t.Helper()
+	var p pokemon.PokemonEndpoint
+	err := json.Unmarshal(body, &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pokemon.Pokemons.Catch(p, 0, "", "")
+}
+
+func level(t *testing.T, ref string) int {
+	t.Helper()
+	p, err := pokemon.Pokemons.Find(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.Level
+}
+
+func expectLines(t *testing.T, output string, want ...string) {
+	t.Helper()
+	got := strings.Split(strings.TrimSpace(output), "\n")
+	if len(got) != len(want) {
+		t.Errorf("expected %d lines, got %d:\n%s", len(want), len(got), output)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+}
+
+func expectContains(t *testing.T, output string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(output, w) {
+			t.Errorf("expected output to contain %q, got:\n%s", w, output)
+		}
+	}
+}
+
+func TestMap(t *testing.T) {
+	c := newTestConfig(t)
+
+	first := strings.Split(strings.TrimSpace(run(t, c, "map")), "\n")
+	if len(first) != 20 || first[0] != "canalave-city-area" || first[19] != "mt-coronet-1f-from-exterior" {
+		t.Errorf("unexpected first page: %v", first)
+	}
+	second := strings.Split(strings.TrimSpace(run(t, c, "map")), "\n")
+	if len(second) != 20 || second[0] != "mt-coronet-1f-route-216" {
+		t.Errorf("unexpected second page: %v", second)
+	}
+	expectLines(t, run(t, c, "map"),
+		"solaceon-ruins-b3f-d",
+		"solaceon-ruins-b3f-e",
+		"solaceon-ruins-b4f-a",
+		"solaceon-ruins-b4f-b",
+		"solaceon-ruins-b4f-c",
+	)
+}
+
+func TestMapb(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "mapb"), "no prev locations to show")
+
+	run(t, c, "map")
+	expectLines(t, run(t, c, "mapb"), "no prev locations to show")
+	run(t, c, "map")
+	run(t, c, "map")
+	second := strings.Split(strings.TrimSpace(run(t, c, "mapb")), "\n")
+	if len(second) != 20 || second[0] != "mt-coronet-1f-route-216" {
+		t.Errorf("expected mapb to go back to the second page, got %v", second)
+	}
+	first := strings.Split(strings.TrimSpace(run(t, c, "mapb")), "\n")
+	if len(first) != 20 || first[0] != "canalave-city-area" {
+		t.Errorf("expected another mapb to go back to the first page, got %v", first)
+	}
+	expectLines(t, run(t, c, "mapb"), "no prev locations to show")
+
+	// map carries on from the page mapb went back to
+	second = strings.Split(strings.TrimSpace(run(t, c, "map")), "\n")
+	if len(second) != 20 || second[0] != "mt-coronet-1f-route-216" {
+		t.Errorf("expected map to go on to the second page, got %v", second)
+	}
+}
+
+func TestMapPages(t *testing.T) {
+	c := newTestConfig(t)
+
+	// 45 areas, the last page has 5
+	last := strings.Split(strings.TrimSpace(run(t, c, "map last")), "\n")
+	if len(last) != 5 || last[4] != "solaceon-ruins-b4f-c" {
+		t.Errorf("unexpected last page: %v", last)
+	}
+	expectLines(t, run(t, c, "map"), "no next locations to show, this is the last page")
+	expectLines(t, run(t, c, "map goto 4"), "there is no page 4 of location-area, pages go from 1 to 3")
+	expectLines(t, run(t, c, "map goto 0"), "there is no page 0 of location-area, pages go from 1 to 3")
+
+	second := strings.Split(strings.TrimSpace(run(t, c, "map goto 2")), "\n")
+	if len(second) != 20 || second[0] != "mt-coronet-1f-route-216" {
+		t.Errorf("unexpected second page: %v", second)
+	}
+	first := strings.Split(strings.TrimSpace(run(t, c, "map first")), "\n")
+	if len(first) != 20 || first[0] != "canalave-city-area" {
+		t.Errorf("unexpected first page: %v", first)
+	}
+	run(t, c, "map first")
+
+	// mapb retraces the pages map went to, not the pages before this one
+	second = strings.Split(strings.TrimSpace(run(t, c, "mapb")), "\n")
+	if len(second) != 20 || second[0] != "mt-coronet-1f-route-216" {
+		t.Errorf("expected mapb to go back to the second page, got %v", second)
+	}
+	expectLines(t, run(t, c, "mapb"), last...)
+	expectLines(t, run(t, c, "mapb"), "no prev locations to show")
+
+	expectLines(t, run(t, c, "map goto"), "usage: map [first|last|goto] [page]")
+	expectLines(t, run(t, c, "map goto two"), "usage: map [first|last|goto] [page]")
+	expectLines(t, run(t, c, "map first 2"), "usage: map [first|last|goto] [page]")
+}
+
+func TestList(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "list pokemon --limit 3"), "pikachu", "raichu", "tentacool", "Page 1 of 3 (7 pokemon)")
+	expectLines(t, run(t, c, "list pokemon next"), "magikarp", "gyarados", "wurmple", "Page 2 of 3 (7 pokemon)")
+	expectLines(t, run(t, c, "list pokemon next"), "budew", "Page 3 of 3 (7 pokemon)")
+	expectLines(t, run(t, c, "list pokemon next"), "already on the last page")
+	expectLines(t, run(t, c, "list pokemon prev"), "magikarp", "gyarados", "wurmple", "Page 2 of 3 (7 pokemon)")
+
+	// types keep a page of their own
+	expectContains(t, run(t, c, "list types --page 1"), "normal", "Page 1 of 1 (18 types)")
+	expectLines(t, run(t, c, "list types prev"), "already on the first page")
+	expectLines(t, run(t, c, "list pokemon"), "magikarp", "gyarados", "wurmple", "Page 2 of 3 (7 pokemon)")
+
+	// a smaller page holds the first pokemon of the page before
+	expectLines(t, run(t, c, "list pokemon --limit 2"), "tentacool", "magikarp", "Page 2 of 4 (7 pokemon)")
+	expectLines(t, run(t, c, "list pokemon last"), "budew", "Page 4 of 4 (7 pokemon)")
+	expectLines(t, run(t, c, "list pokemon --page 9"), "there is no page 9 of pokemon, pages go from 1 to 4")
+
+	expectLines(t, run(t, c, "list abilities"), "can't list abilities (use pokemon, moves, items, berries, types, locations)")
+	expectLines(t, run(t, c, "list pokemon --limit 0"), "usage: --limit takes a number from 1 to 100")
+	expectLines(t, run(t, c, "list pokemon next --page 2"), "usage: "+commands["list"].usage())
+	expectLines(t, run(t, c, "list pokemon sideways"), "usage: "+commands["list"].usage())
+}
+
+func TestSearch(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "search thunder --kind move"), "move          thunder", "move          thunderbolt", "move          thunder-wave", "move          thunder-shock")
+	// the names with a word starting with it, shortest first
+	expectLines(t, run(t, c, "search coronet --kind location-area"),
+		"location-area mt-coronet-2f",
+		"location-area mt-coronet-3f",
+		"location-area mt-coronet-4f",
+		"location-area mt-coronet-5f",
+		"location-area mt-coronet-6f",
+		"location-area mt-coronet-b1f",
+		"location-area mt-coronet-1f-route-207",
+		"location-area mt-coronet-1f-route-211",
+		"location-area mt-coronet-1f-route-216",
+		"location-area mt-coronet-4f-small-room",
+		"location-area mt-coronet-1f-from-exterior",
+		"location-area mt-coronet-exterior-blizzard",
+		"location-area mt-coronet-exterior-snowfall",
+	)
+	expectLines(t, run(t, c, "search pikachoo"), "pokemon       pikachu")
+	expectLines(t, run(t, c, "search zzz"), "Nothing matches zzz")
+	expectLines(t, run(t, c, "search ball --kind berry"), "unknown kind \"berry\" (use pokemon, location-area, move, item)")
+
+	// the index is kept on disk for the next session
+	if _, err := os.Stat(c.indexPath); err != nil {
+		t.Errorf("expected the index to be saved: %v", err)
+	}
+
+	// misspelled names get suggestions
+	expectLines(t, run(t, c, "explore canalave-city-aera"), "invalid location-area-name canalave-city-aera, did you mean canalave-city-area?")
+	expectLines(t, run(t, c, "matchup magikrap"), "invalid pokemon name magikrap, did you mean magikarp?")
+	run(t, c, "goto canalave-city-area")
+	expectLines(t, run(t, c, "catch tentacol"), "There are no tentacol in canalave-city-area, did you mean tentacool or tentacruel?")
+}
+
+func TestExplore(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "explore"), "usage: explore <location-area-name>")
+	expectLines(t, run(t, c, "explore canalave-city-area"),
+		"tentacool",
+		"tentacruel",
+		"wingull",
+		"magikarp",
+		"finneon",
+		"gyarados",
+	)
+	expectLines(t, run(t, c, "explore nowhere-area"), "invalid location-area-name (possible spelling mistakes)")
+}
+
+func TestCatch(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "catch"), "usage: catch <pokemon-name> [--nickname NAME] [--ball BALL] [--status STATUS]")
+	expectLines(t, run(t, c, "catch magikarp"), "You need to be somewhere to catch Pokemons, use goto <location-area-name> first")
+	expectLines(t, run(t, c, "goto canalave-city-area"),
+		"You are now in canalave-city-area",
+		"tentacool",
+		"tentacruel",
+		"wingull",
+		"magikarp",
+		"finneon",
+		"gyarados",
+	)
+	expectLines(t, run(t, c, "catch pikachu"), "There are no pikachu in canalave-city-area")
+
+	// a poke-ball catches a healthy magikarp a third of the time, and the
+	// seeded catcher makes the throws the same every run
+	var res catchResult
+	err := json.Unmarshal([]byte(run(t, c, "catch magikarp --json --ball poke-ball")), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Ball != "poke-ball" || math.Abs(res.Probability-1.0/3) > 0.001 || res.Caught {
+		t.Errorf("unexpected catch result: %+v", res)
+	}
+	expectLines(t, run(t, c, "catch magikarp --ball master-ball"), "You don't have any master-ball in your bag")
+	pokemon.Pokemons.AddItem("master-ball", 1)
+	expectContains(t, run(t, c, "catch magikarp --ball master-ball --status sleep"),
+		"A master-ball had a 100.0% chance of catching magikarp",
+		"magikarp was caught and added to your Pokedex",
+		"It joined your party as #1",
+	)
+	_, err = pokemon.Pokemons.Get("magikarp")
+	if err != nil {
+		t.Error(err)
+	}
+	expectLines(t, run(t, c, "catch magikarp --ball rock"), "unknown ball \"rock\" (use great-ball, master-ball, poke-ball, premier-ball, safari-ball, ultra-ball)")
+	expectLines(t, run(t, c, "catch magikarp --status confused"), "unknown status \"confused\" (use burn, freeze, paralysis, poison, sleep)")
+}
+
+func TestInspect(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "inspect pikachu"), "You don't have the pokemon: pikachu")
+
+	addPokemon(t, fetch(t, c, "pikachu"))
+	expectLines(t, run(t, c, "inspect pikachu"),
+		"Pokemon: pikachu",
+		"Height: 4 | Weight: 60",
+		"Sprite: https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/25.png",
+		"==TYPES==",
+		"electric",
+		"==STATS==",
+		"hp: 35",
+		"attack: 55",
+		"defense: 40",
+		"special-attack: 50",
+		"special-defense: 50",
+		"speed: 90",
+	)
+
+	var res inspectResult
+	err := json.Unmarshal([]byte(run(t, c, "inspect pikachu --sprite shiny --json")), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Sprite != "https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/shiny/25.png" {
+		t.Errorf("expected the shiny sprite, got %s", res.Sprite)
+	}
+	expectLines(t, run(t, c, "inspect pikachu --sprite side"), "unknown sprite \"side\" (use front, shiny, back, gen1)")
+	expectLines(t, run(t, c, "inspect pikachu --sprite gen1"), "pikachu has no gen1 sprite")
+
+	// sprites come from github, not PokeAPI
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.NRGBA{A: 0xff})
+	var sprite bytes.Buffer
+	err = png.Encode(&sprite, img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.client.SetTransport(spriteTransport{png: sprite.Bytes()})
+	expectLines(t, run(t, c, "inspect pikachu --sprite back"),
+		"Pokemon: pikachu",
+		"Height: 4 | Weight: 60",
+		"Sprite: https://raw.githubusercontent.com/PokeAPI/sprites/master/sprites/pokemon/back/25.png",
+		"@",
+		"==TYPES==",
+		"electric",
+		"==STATS==",
+		"hp: 35",
+		"attack: 55",
+		"defense: 40",
+		"special-attack: 50",
+		"special-defense: 50",
+		"speed: 90",
+	)
+}
+
+// spriteTransport answers every request for a sprite with the same png,
+// so drawing one doesn't need the network
+type spriteTransport struct {
+	png []byte
+}
+
+func (s spriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != "raw.githubusercontent.com" {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"image/png"}},
+		Body:       io.NopCloser(bytes.NewReader(s.png)),
+		Request:    req,
+	}, nil
+}
+
+func TestPokedex(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "pokedex"), "You haven't caught any Pokemons...YET!")
+
+	addPokemon(t, fetch(t, c, "pikachu"))
+	addPokemon(t, fetch(t, c, "magikarp"))
+	output := run(t, c, "pokedex")
+	expectContains(t, output, "==Your Pokedex==", "pikachu", "magikarp")
+}
+
+func TestJSONOutput(t *testing.T) {
+	c := newTestConfig(t)
+
+	var explored struct {
+		LocationArea string   `json:"location_area"`
+		Pokemon      []string `json:"pokemon"`
+	}
+	err := json.Unmarshal([]byte(run(t, c, "explore eterna-forest-area --json")), &explored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if explored.LocationArea != "eterna-forest-area" || len(explored.Pokemon) != 5 {
+		t.Errorf("unexpected explore result: %+v", explored)
+	}
+	if c.output != render.Text {
+		t.Error("--json leaked into the next command")
+	}
+
+	addPokemon(t, fetch(t, c, "pikachu"))
+	var inspected inspectResult
+	err = json.Unmarshal([]byte(run(t, c, "inspect pikachu --output json")), &inspected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inspected.Name != "pikachu" || len(inspected.Stats) != 6 || inspected.Stats[5] != (statResult{"speed", 90}) {
+		t.Errorf("unexpected inspect result: %+v", inspected)
+	}
+
+	expectLines(t, run(t, c, "pokedex --output table"),
+		"pokemon:",
+		"  - pikachu",
+	)
+	expectLines(t, run(t, c, "pokedex --output xml"), "usage: unknown output format \"xml\" (use text, json or table)")
+}
+
+func TestScript(t *testing.T) {
+	c := newTestConfig(t)
+	script := filepath.Join(t.TempDir(), "script.txt")
+	err := os.WriteFile(script, []byte("# explore then fail\n\nexplore canalave-city-area\ncatch missingno\nmap\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var code int
+	output := capture(t, func() {
+		code = runScript(context.Background(), c, script)
+	})
+	if code != exitFailed {
+		t.Errorf("expected exit code %d, got %d", exitFailed, code)
+	}
+	expectContains(t, output, "tentacool")
+	if strings.Contains(output, "canalave-city-area") {
+		t.Error("script kept going after a failed command")
+	}
+
+}
+
+func TestRunArgs(t *testing.T) {
+	cases := map[string]int{
+		"explore eterna-forest-area": exitOK,
+		"catch missingno":            exitFailed,
+		"explore":                    exitUsage,
+		"fly canalave-city-area":     exitUsage,
+	}
+	for args, want := range cases {
+		c := newTestConfig(t)
+		var code int
+		capture(t, func() {
+			code = runArgs(context.Background(), c, strings.Fields(args))
+		})
+		if code != want {
+			t.Errorf("%s: expected exit code %d, got %d", args, want, code)
+		}
+	}
+
+	// arguments are passed on as they are, spaces and all
+	c := newTestConfig(t)
+	addPokemon(t, fetch(t, c, "magikarp"))
+	path := filepath.Join(t.TempDir(), "my save.json")
+	err := pokemon.Pokemons.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pokemon.Pokemons.Reset()
+	var code int
+	capture(t, func() {
+		code = runArgs(context.Background(), c, []string{"load", path})
+	})
+	if code != exitOK {
+		t.Errorf("load %q: expected exit code %d, got %d", path, exitOK, code)
+	}
+	if _, err := pokemon.Pokemons.Get("magikarp"); err != nil {
+		t.Errorf("expected the save with a space in its path to be loaded: %v", err)
+	}
+}
+
+func TestLoadWarnings(t *testing.T) {
+	c := newTestConfig(t)
+	err := os.WriteFile(c.savePath, []byte("not json"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(settingsPath(c.savePath), []byte("not json"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// warnings stay out of the output, it might be json going to another program
+	output := capture(t, func() {
+		loadSave(c)
+		loadSettings(c)
+	})
+	if output != "" {
+		t.Errorf("expected warnings on stderr, got %q on stdout", output)
+	}
+	if _, err := os.Stat(c.savePath + ".bak"); err != nil {
+		t.Errorf("expected the broken save to be moved aside: %v", err)
+	}
+}
+
+func TestBattle(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "battle pikachu"), "usage: battle <your-pokemon> <wild-pokemon> [--seed N]")
+	expectLines(t, run(t, c, "battle pikachu magikarp"), "You don't have the pokemon: pikachu")
+
+	addPokemon(t, fetch(t, c, "pikachu"))
+	output := run(t, c, "battle pikachu magikarp --seed 42")
+	expectContains(t, output, "pikachu vs magikarp (seed 42)", "pikachu used thunder-shock", "pikachu won the battle!", "pikachu grew to level 6!")
+	if level := level(t, "pikachu"); level != 6 {
+		t.Errorf("expected pikachu to be level 6 after winning, got %d", level)
+	}
+
+	// levels change the battle, so replay both from the same level
+	replay := func(line string) string {
+		pokemon.Pokemons.SetLevel(1, 20)
+		pokemon.Pokemons.SetDamage(1, 0)
+		return run(t, c, line)
+	}
+	first := replay("battle pikachu magikarp --seed 42 --json")
+	second := replay("battle pikachu magikarp --seed=42 --json")
+	if first != second {
+		t.Errorf("same seed gave different battles:\n%s\n%s", first, second)
+	}
+	var res battleResult
+	err := json.Unmarshal([]byte(first), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Winner != "pikachu" || res.Seed != 42 || res.Level != 21 || !res.LeveledUp || len(res.Events) == 0 {
+		t.Errorf("unexpected battle result: %+v", res)
+	}
+	for _, event := range res.Events {
+		if event.Attacker == "magikarp" && event.Move != "tackle" {
+			t.Errorf("magikarp should only know tackle at level 20, used %s", event.Move)
+		}
+	}
+}
+
+func TestBattleOffline(t *testing.T) {
+	c := newOfflineConfig(t, 1)
+	addPokemon(t, fetch(t, c, "gyarados"))
+	pokemon.Pokemons.SetLevel(1, 20)
+	output := run(t, c, "battle gyarados magikarp --seed 42")
+	expectContains(t, output, "gyarados vs magikarp (seed 42)", "magikarp used tackle", "gyarados won the battle!")
+}
+
+func TestBattleWithoutTypes(t *testing.T) {
+	c := newTestConfig(t)
+	addPokemon(t, fetch(t, c, "pikachu"))
+	pokemon.Pokemons.SetLevel(1, 20)
+	want := run(t, c, "battle pikachu magikarp --seed 42 --json")
+
+	// nothing listens on port 1, so no type can be loaded
+	dead := pokeapi.NewClient("http://127.0.0.1:1/api/v2", time.Second, pokecache.NewCache(time.Minute))
+	dead.SetRetryPolicy(pokeapi.RetryPolicy{MaxAttempts: 1})
+	c.types = typechart.New(dead)
+	pokemon.Pokemons.SetLevel(1, 20)
+	pokemon.Pokemons.SetDamage(1, 0)
+	if got := run(t, c, "battle pikachu magikarp --seed 42 --json"); got != want {
+		t.Errorf("expected the built-in chart to fight like PokeAPI's:\n%s\n%s", want, got)
+	}
+}
+
+func TestNewConfig(t *testing.T) {
+	server := pokeapitest.NewServer(t)
+	client := pokeapi.NewClient(server.BaseURL, time.Second, pokecache.NewCache(time.Minute))
+	c := newConfig(client, 1, render.JSON)
+	if c.client == nil || c.types == nil || c.catcher == nil || c.rng == nil || c.areaPages == nil {
+		t.Fatalf("expected every lookup to be set up, got %+v", c)
+	}
+	if c.output != render.JSON || c.outputDefault != render.JSON {
+		t.Errorf("expected json output, got %s and %s", c.output, c.outputDefault)
+	}
+
+	// the commands that need the type chart work on it as main builds it
+	commands = newCommands()
+	pokemon.Pokemons.Reset()
+	c.savePath = filepath.Join(t.TempDir(), "save.json")
+	addPokemon(t, fetch(t, &c, "magikarp"))
+	for _, line := range []string{"matchup pikachu", "counter pikachu"} {
+		var err error
+		capture(t, func() {
+			err = runCommand(context.Background(), &c, line)
+		})
+		if err != nil {
+			t.Errorf("%s: %v", line, err)
+		}
+	}
+}
+
+func TestMatchup(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "matchup"), "usage: matchup <pokemon-name>")
+	expectLines(t, run(t, c, "matchup gyarados"),
+		"gyarados (water/flying)",
+		"==WEAK TO==",
+		"electric: 4x",
+		"rock: 2x",
+		"==RESISTS==",
+		"bug: 0.5x",
+		"fighting: 0.5x",
+		"fire: 0.5x",
+		"steel: 0.5x",
+		"water: 0.5x",
+		"==IMMUNE TO==",
+		"ground",
+	)
+}
+
+func TestMatchupOffline(t *testing.T) {
+	c := newOfflineConfig(t, 1)
+	expectContains(t, run(t, c, "matchup gyarados"), "gyarados (water/flying)", "electric: 4x", "ground")
+
+	addPokemon(t, fetch(t, c, "tentacruel"))
+	addPokemon(t, fetch(t, c, "magikarp"))
+	expectLines(t, run(t, c, "counter gyarados"),
+		"Best counters for gyarados (water/flying) in your Pokedex:",
+		"tentacruel (water/poison): deals 1x, takes 1x",
+		"magikarp (water): deals 0.5x, takes 1x",
+	)
+}
+
+func TestCounter(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "counter gyarados"), "You haven't caught any Pokemons to counter gyarados with")
+
+	addPokemon(t, fetch(t, c, "magikarp"))
+	addPokemon(t, fetch(t, c, "pikachu"))
+	addPokemon(t, fetch(t, c, "budew"))
+	expectLines(t, run(t, c, "counter gyarados"),
+		"Best counters for gyarados (water/flying) in your Pokedex:",
+		"pikachu (electric): deals 4x, takes 1x",
+		"budew (grass/poison): deals 1x, takes 2x",
+		"magikarp (water): deals 0.5x, takes 1x",
+	)
+}
+
+func TestEvolutions(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "evolutions"), "usage: evolutions <pokemon-name>")
+	expectLines(t, run(t, c, "evolutions pikachu"),
+		"pichu",
+		"└── pikachu (level-up: happiness 220)",
+		"    └── raichu (use-item: thunder-stone)",
+	)
+	expectLines(t, run(t, c, "evolutions wurmple"),
+		"wurmple",
+		"├── silcoon (level-up: level 7)",
+		"│   └── beautifly (level-up: level 10)",
+		"└── cascoon (level-up: level 7)",
+		"    └── dustox (level-up: level 10)",
+	)
+}
+
+func TestEvolve(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "evolve magikarp"), "You don't have the pokemon: magikarp")
+
+	addPokemon(t, fetch(t, c, "magikarp"))
+	expectLines(t, run(t, c, "evolve magikarp"), "magikarp can't evolve yet: gyarados needs level 20 (magikarp is level 5)")
+	expectLines(t, run(t, c, "evolve magikarp pikachu"), "magikarp doesn't evolve into pikachu")
+
+	pokemon.Pokemons.SetLevel(1, 20)
+	expectLines(t, run(t, c, "evolve magikarp"), "Congratulations! Your magikarp evolved into gyarados!")
+	expectContains(t, run(t, c, "party list"), "1. gyarados #1 lv20")
+	if level := level(t, "gyarados"); level != 20 {
+		t.Errorf("expected gyarados to keep level 20, got %d", level)
+	}
+	expectLines(t, run(t, c, "evolve gyarados"), "gyarados doesn't evolve any further")
+
+	addPokemon(t, fetch(t, c, "pikachu"))
+	expectLines(t, run(t, c, "evolve pikachu"), "pikachu can't evolve yet: raichu needs a thunder-stone (use thunder-stone pikachu)")
+}
+
+func TestEvolveOffline(t *testing.T) {
+	c := newOfflineConfig(t, 1)
+	expectLines(t, run(t, c, "evolutions tentacruel"),
+		"tentacool",
+		"└── tentacruel (level-up: level 30)",
+	)
+
+	addPokemon(t, fetch(t, c, "magikarp"))
+	pokemon.Pokemons.SetLevel(1, 20)
+	expectLines(t, run(t, c, "evolve magikarp"), "Congratulations! Your magikarp evolved into gyarados!")
+}
+
+func TestParty(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "party"), "usage: "+partyUsageText)
+	expectLines(t, run(t, c, "party list"), "==PARTY==", "Your party is empty")
+
+	for i := 0; i < pokemon.PartySize; i++ {
+		addPokemon(t, fetch(t, c, "magikarp"))
+	}
+	addPokemon(t, fetch(t, c, "pikachu"))
+	expectLines(t, run(t, c, "pokedex"), "==Your Pokedex==", "magikarp", "pikachu")
+	expectLines(t, run(t, c, "party list"),
+		"==PARTY==",
+		"1. magikarp #1 lv5",
+		"2. magikarp #2 lv5",
+		"3. magikarp #3 lv5",
+		"4. magikarp #4 lv5",
+		"5. magikarp #5 lv5",
+		"6. magikarp #6 lv5",
+		"==BOX==",
+		"pikachu #7 lv5",
+	)
+
+	expectLines(t, run(t, c, "party add pikachu"), "Your party is full, remove a pokemon first")
+	run(t, c, "party remove #3")
+	run(t, c, "party add pikachu")
+	expectLines(t, run(t, c, "party swap 1 6"),
+		"==PARTY==",
+		"1. pikachu #7 lv5",
+		"2. magikarp #2 lv5",
+		"3. magikarp #4 lv5",
+		"4. magikarp #5 lv5",
+		"5. magikarp #6 lv5",
+		"6. magikarp #1 lv5",
+		"==BOX==",
+		"magikarp #3 lv5",
+	)
+	expectLines(t, run(t, c, "party swap 1 9"), "no pokemon in party slot 9 (your party has 6)")
+	expectLines(t, run(t, c, "party remove 42"), "You don't have a pokemon with ID #42")
+
+	// the table shows every field of a member
+	expectContains(t, run(t, c, "party list --output table"), "id: 7", "species: pikachu", "level: 5")
+}
+
+func TestCatchNickname(t *testing.T) {
+	c := newTestConfig(t)
+
+	run(t, c, "goto canalave-city-area")
+	expectLines(t, run(t, c, "catch magikarp --nickname 12"), "nicknames can't be numbers")
+	for i := 0; i < 20 && len(pokemon.Pokemons.Instances()) == 0; i++ {
+		output := run(t, c, "catch magikarp --nickname Splashy")
+		if strings.Contains(output, "was caught") {
+			expectContains(t, output, "It joined your party as #1")
+		}
+	}
+	expectLines(t, run(t, c, "party list"), "==PARTY==", "1. Splashy (magikarp) #1 lv3")
+	expectContains(t, run(t, c, "battle splashy wurmple --seed 1"), "Splashy vs wurmple (seed 1)")
+	p, _ := pokemon.Pokemons.Find("splashy")
+	if p.Location != "canalave-city-area" || p.Level < 3 || p.Level > 15 {
+		t.Errorf("expected a level 3-15 magikarp from canalave-city-area, got %+v", p)
+	}
+}
+
+func TestBag(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "bag"),
+		"==Your Bag==",
+		"great-ball x3: Tries to catch a wild Pokémon.  Success rate is 1.5×.",
+		"oran-berry x2: Held: Consumed when HP falls below 50% to restore 10 HP.",
+		"poke-ball x10: Used for catching Pokémon.",
+		"potion x3: Restores 20 HP.",
+	)
+
+	// every throw uses up a ball, caught or not, and magikarp always
+	// shows up on the old rod in canalave-city-area
+	run(t, c, "goto canalave-city-area")
+	run(t, c, "catch magikarp --ball great-ball")
+	run(t, c, "catch magikarp --ball great-ball")
+	run(t, c, "catch magikarp --ball great-ball")
+	if n := pokemon.Pokemons.Count("great-ball"); n != 0 {
+		t.Errorf("expected every great-ball to be used, %d left", n)
+	}
+	expectLines(t, run(t, c, "catch magikarp --ball great-ball"), "You don't have any great-ball in your bag")
+	expectContains(t, run(t, c, "catch magikarp"), "A poke-ball had a")
+	if n := pokemon.Pokemons.Count("poke-ball"); n != 9 {
+		t.Errorf("expected catch to use a poke-ball by default, %d left", n)
+	}
+}
+
+func TestBagOffline(t *testing.T) {
+	c := newOfflineConfig(t, 1)
+	expectLines(t, run(t, c, "bag"),
+		"==Your Bag==",
+		"great-ball x3: Tries to catch a wild Pokémon.  Success rate is 1.5×.",
+		"oran-berry x2: Held: Consumed when HP falls below 50% to restore 10 HP.",
+		"poke-ball x10: Used for catching Pokémon.",
+		"potion x3: Restores 20 HP.",
+	)
+
+	addPokemon(t, fetch(t, c, "magikarp"))
+	expectLines(t, run(t, c, "use potion magikarp"), "magikarp is already at full HP")
+	pokemon.Pokemons.AddItem("thunder-stone", 1)
+	expectLines(t, run(t, c, "use thunder-stone magikarp"), "magikarp can't evolve yet: gyarados doesn't evolve with a thunder-stone")
+}
+
+type countingTransport struct {
+	requests *int
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	*t.requests++
+	return nil, errors.New("connection reset")
+}
+
+func TestBagUnreachable(t *testing.T) {
+	c := newTestConfig(t)
+	requests := 0
+	c.client.SetTransport(countingTransport{requests: &requests})
+	expectLines(t, run(t, c, "bag"),
+		"==Your Bag==",
+		"great-ball x3",
+		"oran-berry x2",
+		"poke-ball x10",
+		"potion x3",
+	)
+	if requests != 1 {
+		t.Errorf("expected the bag to stop looking items up after the first failure, made %d requests", requests)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := runCommand(ctx, c, "bag")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected an interrupted bag to be cancelled, got %v", err)
+	}
+}
+
+func TestUse(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "use"), "usage: use <item> <pokemon>")
+	expectLines(t, run(t, c, "use thunder-stone pikachu"), "You don't have any thunder-stone in your bag")
+	expectLines(t, run(t, c, "use poke-ball pikachu"), "usage: catch <pokemon-name> --ball poke-ball")
+
+	addPokemon(t, fetch(t, c, "pikachu"))
+	// pikachu has 18 HP at level 5
+	expectLines(t, run(t, c, "use potion pikachu"), "pikachu is already at full HP")
+	pokemon.Pokemons.SetDamage(1, 15)
+	expectLines(t, run(t, c, "use oran-berry pikachu"), "Used oran-berry on pikachu, it has 13/18 HP")
+	expectLines(t, run(t, c, "use potion pikachu"), "Used potion on pikachu, it has 18/18 HP")
+	if n := pokemon.Pokemons.Count("potion"); n != 2 {
+		t.Errorf("expected 2 potions left, got %d", n)
+	}
+
+	pokemon.Pokemons.SetDamage(1, 18)
+	expectLines(t, run(t, c, "battle pikachu magikarp"), "pikachu has fainted, use a revive on it first")
+	expectLines(t, run(t, c, "use potion pikachu"), "pikachu has fainted, it needs a revive")
+	pokemon.Pokemons.AddItem("revive", 1)
+	expectLines(t, run(t, c, "use revive pikachu"), "Used revive on pikachu, it has 9/18 HP")
+
+	pokemon.Pokemons.AddItem("rare-candy", 1)
+	expectLines(t, run(t, c, "use rare-candy pikachu"), "Used rare-candy on pikachu, it grew to level 6!")
+
+	pokemon.Pokemons.AddItem("thunder-stone", 1)
+	addPokemon(t, fetch(t, c, "magikarp"))
+	expectLines(t, run(t, c, "use thunder-stone magikarp"), "magikarp can't evolve yet: gyarados doesn't evolve with a thunder-stone")
+	if n := pokemon.Pokemons.Count("thunder-stone"); n != 1 {
+		t.Errorf("a thunder-stone was used up without doing anything")
+	}
+	expectLines(t, run(t, c, "use thunder-stone pikachu"), "Used thunder-stone on pikachu, it evolved into raichu!")
+	expectLines(t, run(t, c, "bag"),
+		"==Your Bag==",
+		"great-ball x3: Tries to catch a wild Pokémon.  Success rate is 1.5×.",
+		"oran-berry x1: Held: Consumed when HP falls below 50% to restore 10 HP.",
+		"poke-ball x10: Used for catching Pokémon.",
+		"potion x2: Restores 20 HP.",
+	)
+}
+
+func TestCatchAppearing(t *testing.T) {
+	c := newTestConfig(t)
+
+	// pikachu shows up 10% of the time in eterna-forest-area
+	run(t, c, "goto eterna-forest-area")
+	seen := 0
+	for i := 0; i < 20; i++ {
+		output := run(t, c, "catch pikachu --json")
+		var res catchResult
+		err := json.Unmarshal([]byte(output), &res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Method != "walk" || res.Level < 10 || res.Level > 12 {
+			t.Errorf("unexpected encounter: %+v", res)
+		}
+		// the keys are there whether or not a ball was thrown
+		var fields map[string]any
+		err = json.Unmarshal([]byte(output), &fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, key := range []string{"ball", "probability"} {
+			if _, ok := fields[key]; !ok {
+				t.Errorf("expected %s in %s", key, output)
+			}
+		}
+		if res.Appeared {
+			seen++
+		}
+	}
+	if seen == 0 || seen > 10 {
+		t.Errorf("pikachu showed up %d times in 20 tries", seen)
+	}
+	if n := pokemon.Pokemons.Count("poke-ball"); n != pokemon.StarterBag["poke-ball"]-seen {
+		t.Errorf("expected a ball thrown only when pikachu showed up, %d left after %d sightings", n, seen)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "walk"), "You need to be somewhere to look for Pokemons, use goto <location-area-name> first")
+	run(t, c, "goto canalave-city-area")
+	expectLines(t, run(t, c, "walk"), "No Pokemons can be found by walk in canalave-city-area (try surf, old-rod, good-rod, super-rod)")
+	expectLines(t, run(t, c, "walk --method surf --version red"), "No Pokemons can be found in canalave-city-area")
+	expectLines(t, run(t, c, "walk --version diamnod"), "unknown version \"diamnod\" (try red, diamond or platinum)")
+
+	first := run(t, c, "encounter --method super-rod --seed 3")
+	expectLines(t, first, "A wild gyarados (lv49) appeared by super-rod in canalave-city-area! (seed 3)")
+	if second := run(t, c, "walk --method super-rod --seed 3 --version diamond"); second != first {
+		t.Errorf("same seed gave different encounters:\n%s\n%s", first, second)
+	}
+
+	// every surf encounter is one of the surf slots, weighted by chance
+	counts := make(map[string]int)
+	for i := 0; i < 200; i++ {
+		var res walkResult
+		err := json.Unmarshal([]byte(run(t, c, "walk --method surf --json")), &res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[res.Wild.Pokemon]++
+	}
+	if len(counts) != 3 || counts["tentacool"] < counts["wingull"] || counts["wingull"] < counts["tentacruel"] {
+		t.Errorf("unexpected surf encounters: %v", counts)
+	}
+
+	// the pokemon found by walk is the one catch goes after
+	run(t, c, "walk --method super-rod --seed 3")
+	pokemon.Pokemons.AddItem("master-ball", 1)
+	expectContains(t, run(t, c, "catch gyarados --ball master-ball"), "A wild gyarados (lv49) appeared by super-rod!")
+	if level(t, "gyarados") != 49 {
+		t.Errorf("expected the gyarados from the walk, got level %d", level(t, "gyarados"))
+	}
+}
+
+func TestVersion(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "version"), "Playing every version, use version <version-name> to pick one")
+	expectLines(t, run(t, c, "version ruby"), `unknown version "ruby" (try red, diamond or platinum)`)
+	expectLines(t, run(t, c, "version platinum"), "Playing platinum (platinum, generation 4)")
+
+	// the version outlives the session
+	next := newTestConfig(t)
+	next.savePath = c.savePath
+	loadSettings(next)
+	if next.game.Version != "platinum" || next.game.VersionGroup != "platinum" {
+		t.Errorf("expected platinum after loading the settings, got %+v", next.game)
+	}
+
+	// nothing lives in canalave in red
+	expectLines(t, run(t, c, "version red"), "Playing red (red-blue, generation 1)")
+	if output := run(t, c, "explore canalave-city-area"); output != "" {
+		t.Errorf("expected no pokemon in red, got:\n%s", output)
+	}
+	expectLines(t, run(t, c, "goto canalave-city-area"), "You are now in canalave-city-area")
+	expectLines(t, run(t, c, "catch magikarp"), "There are no magikarp in canalave-city-area in red")
+	expectLines(t, run(t, c, "walk --method surf"), "No Pokemons can be found in canalave-city-area")
+
+	// and pikachu has no moves from red in the fixtures
+	addPokemon(t, fetch(t, c, "pikachu"))
+	var res battleResult
+	err := json.Unmarshal([]byte(run(t, c, "battle pikachu magikarp --seed 1 --json")), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range res.Events {
+		if event.Attacker == "pikachu" && event.Move == "thunder-shock" {
+			t.Fatalf("pikachu used a move it didn't have in red: %+v", event)
+		}
+	}
+
+	expectLines(t, run(t, c, "version all"), "Playing every version, use version <version-name> to pick one")
+	expectContains(t, run(t, c, "explore canalave-city-area"), "magikarp")
+}
+
+func TestComplete(t *testing.T) {
+	c := newTestConfig(t)
+	complete := completer(c)
+
+	if names := complete("ma"); !slices.Contains(names, "map") || !slices.Contains(names, "matchup") {
+		t.Errorf("expected command names, got %v", names)
+	}
+	if names := complete("explore "); len(names) != 0 {
+		t.Errorf("expected no areas before map, got %v", names)
+	}
+	run(t, c, "map")
+	if names := complete("explore "); len(names) != 20 || names[0] != "canalave-city-area" {
+		t.Errorf("expected the areas of the last map page, got %v", names)
+	}
+
+	addPokemon(t, fetch(t, c, "pikachu"))
+	magikarp, err := pokemon.Info(context.Background(), c.client, "magikarp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pokemon.Pokemons.Catch(magikarp, 0, "", "Splashy")
+	if names := complete("battle "); !slices.Equal(names, []string{"magikarp", "pikachu", "Splashy"}) {
+		t.Errorf("expected caught pokemon and nicknames, got %v", names)
+	}
+	if names := complete("inspect p"); !slices.Equal(names, []string{"magikarp", "pikachu"}) {
+		t.Errorf("expected the species in the Pokedex, got %v", names)
+	}
+	if names := complete("use "); !slices.Contains(names, "potion") {
+		t.Errorf("expected bag items, got %v", names)
+	}
+	if names := complete("party add "); !slices.Contains(names, "Splashy") {
+		t.Errorf("expected caught pokemon for party add, got %v", names)
+	}
+	if names := complete("pokedex "); len(names) != 0 {
+		t.Errorf("expected nothing for pokedex, got %v", names)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	c := newTestConfig(t)
+
+	expectLines(t, run(t, c, "inspect"), "usage: inspect <pokemon-name> [--sprite front|shiny|back|gen1]")
+	expectLines(t, run(t, c, "pokedex pikachu"), "usage: pokedex")
+	expectLines(t, run(t, c, "map --seed 3"), "usage: map doesn't take --seed (map [first|last|goto] [page])")
+	expectLines(t, run(t, c, "cacth pikachu"), "unknown command: cacth, did you mean catch?")
+	expectLines(t, run(t, c, "fly canalave-city-area"), `unknown command: fly (use "help" to see every command)`)
+	expectLines(t, run(t, c, "help fly"), `unknown command: fly (use "help" to see every command)`)
+
+	expectLines(t, run(t, c, "help walk"),
+		"usage: walk [--method walk|surf|old-rod|...] [--version NAME] [--seed N]",
+		"",
+		"looks for a wild Pokemon in the area you are in, weighted by how common each one is",
+		"",
+		"aliases: encounter",
+		"",
+		"FLAGS:",
+		"--method walk|surf|old-rod|...",
+		"    how to look, walking through grass by default",
+		"--version NAME",
+		"    the game version whose encounter table is used",
+		"--seed N",
+		"    finds the same Pokemon as an earlier walk with this seed",
+	)
+	// aliases work for help too
+	if run(t, c, "help encounter") != run(t, c, "help walk") {
+		t.Error("expected help for an alias to be the help of its command")
+	}
+	expectContains(t, run(t, c, "help"), "=======Pokedex help center=======", "evolve <pokemon-name> [into]", "load <save-file>")
+
+	// every command's usage is what its usage errors say
+	for name, cmd := range commands {
+		required := slices.ContainsFunc(cmd.args, func(arg argument) bool { return !arg.optional })
+		if !required || cmd.usageText != "" {
+			continue
+		}
+		expectLines(t, run(t, c, name), "usage: "+cmd.usage())
+	}
+}
+
+func TestErrors(t *testing.T) {
+	c := newTestConfig(t)
+	printed := func(line string) string {
+		return capture(t, func() {
+			err := runCommand(context.Background(), c, line)
+			if err != nil {
+				printError(os.Stdout, c, err, "")
+			}
+		})
+	}
+
+	for _, line := range []string{"", "   ", "\t"} {
+		if output := printed(line); output != "" {
+			t.Errorf("expected nothing for %q, got %q", line, output)
+		}
+	}
+	expectLines(t, printed("--json"), "usage: <command> [arguments] [--flags]")
+
+	// a command that panics stops, the session doesn't
+	commands["boom"] = command{name: "boom", description: "panics", callback: func(context.Context, *config, ...string) (any, error) {
+		var m map[string]int
+		m["boom"]++
+		return nil, nil
+	}}
+	expectLines(t, printed("boom"), "internal error: assignment to entry in nil map (this is a bug, the command was stopped)")
+	expectContains(t, printed("explore canalave-city-area"), "magikarp")
+
+	c.outputDefault = render.JSON
+	var res errorResult
+	err := json.Unmarshal([]byte(printed("inspect pikachu")), &res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Kind != kindUser || res.Message != "You don't have the pokemon: pikachu" {
+		t.Errorf("unexpected json error: %+v", res)
+	}
+	c.outputDefault = render.Text
+
+	// nothing listens on port 1
+	c.client = pokeapi.NewClient("http://127.0.0.1:1/api/v2", time.Second, pokecache.NewCache(time.Minute))
+	c.client.SetRetryPolicy(pokeapi.RetryPolicy{MaxAttempts: 1})
+	expectContains(t, printed("explore canalave-city-area"), "network error: ", "(check your connection, or use --offline with a snapshot)")
+
+	cases := map[string]int{
+		"explore canalave-city-area": exitUnavailable,
+		"boom":                       exitSoftware,
+		"pokedex --seed 1":           exitUsage,
+		"inspect pikachu":            exitFailed,
+		"pokedex":                    exitOK,
+	}
+	for args, want := range cases {
+		var code int
+		capture(t, func() {
+			code = runArgs(context.Background(), c, strings.Fields(args))
+		})
+		if code != want {
+			t.Errorf("%s: expected exit code %d, got %d", args, want, code)
+		}
+	}
+}
+
+func TestOfflineErrors(t *testing.T) {
+	c := newTestConfig(t)
+	path := filepath.Join(t.TempDir(), "snapshot.zip")
+	w, err := snapshot.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := snapshot.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	c.client.SetTransport(archive)
+	c.offline = true
+
+	err = runCommand(context.Background(), c, "list moves")
+	if code := exitCode(err); code != exitFailed {
+		t.Errorf("expected exit code %d for a resource missing from the snapshot, got %d", exitFailed, code)
+	}
+	var b bytes.Buffer
+	printError(&b, c, err, "")
+	expectLines(t, b.String(), "move/offset=0&limit=20 is not in the offline snapshot (response status code: 404)")
+
+	// an offline session has no connection to check
+	b.Reset()
+	printError(&b, c, &url.Error{Op: "Get", URL: "http://pokeapi.co", Err: &net.OpError{Op: "dial", Err: errors.New("refused")}}, "")
+	if strings.Contains(b.String(), "--offline") {
+		t.Errorf("offline sessions shouldn't be told to use --offline: %q", b.String())
+	}
+}
+
+func TestOfflineMisses(t *testing.T) {
+	c := newOfflineConfig(t, 1)
+	// names that exist but weren't crawled aren't spelling mistakes
+	expectLines(t, run(t, c, "explore eterna-forest-area"), "location-area/eterna-forest-area is not in the offline snapshot (response status code: 404)")
+	expectLines(t, run(t, c, "goto eterna-forest-area"), "location-area/eterna-forest-area is not in the offline snapshot (response status code: 404)")
+	expectLines(t, run(t, c, "matchup pikachu"), "pokemon/pikachu is not in the offline snapshot (response status code: 404)")
+	if _, err := os.Stat(c.indexPath); err == nil {
+		t.Errorf("expected no search index to be built from the snapshot")
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	c := newTestConfig(t)
+	quit := 0
+	interrupts := newInterrupter(func() { quit++ })
+
+	// ctrl-c while a command runs cancels it and goes back to the prompt
+	ctx, done := interrupts.start(context.Background())
+	if !interrupts.interrupt() {
+		t.Error("expected the first ctrl-c to only cancel the command")
+	}
+	var err error
+	capture(t, func() {
+		err = runCommand(ctx, c, "explore canalave-city-area")
+	})
+	done()
+	if kindOf(err) != kindInterrupted {
+		t.Errorf("expected an interrupted error, got %v", err)
+	}
+	var buf strings.Builder
+	printError(&buf, c, err, "")
+	expectLines(t, buf.String(), "interrupted (press Ctrl-C again to save and exit)")
+
+	// a second press in a row quits
+	interrupts.interrupt()
+	if quit != 1 {
+		t.Errorf("expected the second ctrl-c to quit, quit %d times", quit)
+	}
+	interrupts.reset()
+	interrupts.interrupt()
+	if quit != 1 {
+		t.Error("expected ctrl-c after anything else not to quit")
+	}
+
+	// a script stops at the interrupted line with the exit code of SIGINT
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var code int
+	capture(t, func() {
+		code = runArgs(ctx, c, []string{"explore", "canalave-city-area"})
+	})
+	if code != exitInterrupted {
+		t.Errorf("expected exit code %d, got %d", exitInterrupted, code)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"github.com/srijan-raghavula/pokedex/internal/typechart"
+	"io"
+	"sort"
+	"strings"
+)
+
+// a pokemon from your Pokedex if you have it, otherwise from PokeAPI
+func lookupPokemon(ctx context.Context, c *config, name string) (pokemon.PokemonEndpoint, error) {
+	p, err := pokemon.Pokemons.Get(name)
+	if err == nil {
+		return p, nil
+	}
+	return pokemon.Info(ctx, c.client, name)
+}
+
+func matchup(ctx context.Context, c *config, name ...string) (any, error) {
+	p, err := lookupPokemon(ctx, c, name[0])
+	if err != nil {
+		return nil, err
+	}
+	types := c.game.Types(p)
+	err = c.types.Load(ctx, types...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := matchupResult{
+		Pokemon:     p.Name,
+		Types:       types,
+		Weaknesses:  []typechart.Matchup{},
+		Resistances: []typechart.Matchup{},
+		Immunities:  []string{},
+	}
+	for _, m := range c.types.Matchups(types...) {
+		switch {
+		case m.Multiplier > 1:
+			res.Weaknesses = append(res.Weaknesses, m)
+		case m.Multiplier == 0:
+			res.Immunities = append(res.Immunities, m.Type)
+		default:
+			res.Resistances = append(res.Resistances, m)
+		}
+	}
+	return res, nil
+}
+
+func counter(ctx context.Context, c *config, name ...string) (any, error) {
+	target, err := lookupPokemon(ctx, c, name[0])
+	if err != nil {
+		return nil, err
+	}
+	targetTypes := c.game.Types(target)
+	err = c.types.Load(ctx, targetTypes...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := counterResult{Pokemon: target.Name, Types: targetTypes, Counters: []counterEntry{}}
+	for _, p := range pokemon.Pokemons.All() {
+		types := c.game.Types(p)
+		err := c.types.Load(ctx, types...)
+		if err != nil {
+			return nil, err
+		}
+		offense, defense := c.types.Score(types, targetTypes)
+		res.Counters = append(res.Counters, counterEntry{
+			Name:    p.Name,
+			Types:   types,
+			Offense: offense,
+			Defense: defense,
+			// a 4x weakness is as bad as an immunity is good, so keep
+			// defense from ever dividing by zero
+			Score: offense / max(defense, 0.125),
+		})
+	}
+	if len(res.Counters) == 0 {
+		return nil, fmt.Errorf("You haven't caught any Pokemons to counter %s with", target.Name)
+	}
+	sort.SliceStable(res.Counters, func(i, j int) bool {
+		return res.Counters[i].Score > res.Counters[j].Score
+	})
+	return res, nil
+}
+
+type matchupResult struct {
+	Pokemon     string              `json:"pokemon"`
+	Types       []string            `json:"types"`
+	Weaknesses  []typechart.Matchup `json:"weaknesses"`
+	Resistances []typechart.Matchup `json:"resistances"`
+	Immunities  []string            `json:"immunities"`
+}
+
+func (r matchupResult) Text(w io.Writer) error {
+	fmt.Fprintf(w, "%s (%s)\n", r.Pokemon, strings.Join(r.Types, "/"))
+	fmt.Fprintln(w, "==WEAK TO==")
+	for _, m := range r.Weaknesses {
+		fmt.Fprintf(w, "%s: %vx\n", m.Type, m.Multiplier)
+	}
+	fmt.Fprintln(w, "==RESISTS==")
+	for _, m := range r.Resistances {
+		fmt.Fprintf(w, "%s: %vx\n", m.Type, m.Multiplier)
+	}
+	fmt.Fprintln(w, "==IMMUNE TO==")
+	for _, t := range r.Immunities {
+		fmt.Fprintln(w, t)
+	}
+	return nil
+}
+
+type counterEntry struct {
+	Name    string   `json:"name"`
+	Types   []string `json:"types"`
+	Offense float64  `json:"offense"`
+	Defense float64  `json:"defense"`
+	Score   float64  `json:"score"`
+}
+
+type counterResult struct {
+	Pokemon  string         `json:"pokemon"`
+	Types    []string       `json:"types"`
+	Counters []counterEntry `json:"counters"`
+}
+
+func (r counterResult) Text(w io.Writer) error {
+	fmt.Fprintf(w, "Best counters for %s (%s) in your Pokedex:\n", r.Pokemon, strings.Join(r.Types, "/"))
+	for _, entry := range r.Counters {
+		fmt.Fprintf(w, "%s (%s): deals %vx, takes %vx\n", entry.Name, strings.Join(entry.Types, "/"), entry.Offense, entry.Defense)
+	}
+	return nil
+}
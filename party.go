@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"io"
+	"strconv"
+)
+
+const partyUsageText = "party list | party add <pokemon> | party remove <pokemon> | party swap <slot> <slot>"
+
+// pokemon are referred to by ID (#3), nickname or species
+func party(ctx context.Context, c *config, args ...string) (any, error) {
+	if len(args) < 1 {
+		return nil, usageError(partyUsageText)
+	}
+	switch args[0] {
+	case "list":
+	case "add", "remove":
+		if len(args) < 2 {
+			return nil, usageError("party " + args[0] + " <pokemon>")
+		}
+		var err error
+		if args[0] == "add" {
+			_, err = pokemon.Pokemons.AddToParty(args[1])
+		} else {
+			_, err = pokemon.Pokemons.RemoveFromParty(args[1])
+		}
+		if err != nil {
+			return nil, err
+		}
+	case "swap":
+		if len(args) < 3 {
+			return nil, usageError("party swap <slot> <slot>")
+		}
+		a, errA := strconv.Atoi(args[1])
+		b, errB := strconv.Atoi(args[2])
+		if errA != nil || errB != nil {
+			return nil, usageError("party swap <slot> <slot>")
+		}
+		err := pokemon.Pokemons.SwapParty(a, b)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, usageError(partyUsageText)
+	}
+	return newPartyResult(), nil
+}
+
+type partyResult struct {
+	Party []pokemon.Caught `json:"party"`
+	// everything caught that isn't in the party
+	Box []pokemon.Caught `json:"box"`
+}
+
+func newPartyResult() partyResult {
+	res := partyResult{
+		Party: pokemon.Pokemons.PartyMembers(),
+		Box:   []pokemon.Caught{},
+	}
+	inParty := make(map[int]bool)
+	for _, p := range res.Party {
+		inParty[p.ID] = true
+	}
+	for _, p := range pokemon.Pokemons.Instances() {
+		if !inParty[p.ID] {
+			res.Box = append(res.Box, p)
+		}
+	}
+	return res
+}
+
+func (r partyResult) Text(w io.Writer) error {
+	fmt.Fprintln(w, "==PARTY==")
+	if len(r.Party) == 0 {
+		fmt.Fprintln(w, "Your party is empty")
+	}
+	for i, p := range r.Party {
+		fmt.Fprintf(w, "%d. %s\n", i+1, member(p))
+	}
+	if len(r.Box) > 0 {
+		fmt.Fprintln(w, "==BOX==")
+		for _, p := range r.Box {
+			fmt.Fprintln(w, member(p))
+		}
+	}
+	return nil
+}
+
+// like "magikarp #1 lv5", or "karpy (magikarp) #1 lv5" with a nickname
+func member(p pokemon.Caught) string {
+	if p.Nickname != "" {
+		return fmt.Sprintf("%s (%s) #%d lv%d", p.Nickname, p.Species, p.ID, p.Level)
+	}
+	return fmt.Sprintf("%s #%d lv%d", p.Species, p.ID, p.Level)
+}
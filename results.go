@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokeapi"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"io"
+	"strings"
+)
+
+// every command returns one of these and runCommand renders it in the
+// chosen output format. the json tags are the --output json schema, so
+// fields can be added but never renamed or removed
+
+type commandHelp struct {
+	Name        string     `json:"name"`
+	Aliases     []string   `json:"aliases,omitempty"`
+	Usage       string     `json:"usage"`
+	Description string     `json:"description"`
+	Flags       []flagHelp `json:"flags,omitempty"`
+}
+
+type flagHelp struct {
+	Flag        string `json:"flag"`
+	Description string `json:"description"`
+}
+
+func (r commandHelp) Text(w io.Writer) error {
+	fmt.Fprintf(w, "usage: %s\n\n%s\n", r.Usage, r.Description)
+	if len(r.Aliases) > 0 {
+		fmt.Fprintf(w, "\naliases: %s\n", strings.Join(r.Aliases, ", "))
+	}
+	if len(r.Flags) > 0 {
+		fmt.Fprintln(w, "\nFLAGS:")
+		for _, flag := range r.Flags {
+			fmt.Fprintf(w, "%s\n    %s\n", flag.Flag, flag.Description)
+		}
+	}
+	return nil
+}
+
+type helpResult struct {
+	Commands []commandHelp `json:"commands"`
+}
+
+func (r helpResult) Text(w io.Writer) error {
+	fmt.Fprintln(w, "=======Pokedex help center=======")
+	fmt.Fprintln(w, "\nusage: [command] (help [command] tells you everything about one)\n\nCOMMANDS TO USE:")
+	for _, cmd := range r.Commands {
+		fmt.Fprintf(w, "%v\n", cmd.Usage)
+		fmt.Fprintf(w, "%v\n\n", cmd.Description)
+	}
+	return nil
+}
+
+type locationsResult struct {
+	LocationAreas []string `json:"location_areas"`
+}
+
+func newLocationsResult(locations pokeapi.ResourceList) locationsResult {
+	res := locationsResult{LocationAreas: []string{}}
+	for _, result := range locations.Results {
+		res.LocationAreas = append(res.LocationAreas, result.Name)
+	}
+	return res
+}
+
+func (r locationsResult) Text(w io.Writer) error {
+	for _, name := range r.LocationAreas {
+		fmt.Fprintln(w, name)
+	}
+	return nil
+}
+
+type exploreResult struct {
+	LocationArea string   `json:"location_area"`
+	Pokemon      []string `json:"pokemon"`
+}
+
+func (r exploreResult) Text(w io.Writer) error {
+	for _, name := range r.Pokemon {
+		fmt.Fprintln(w, name)
+	}
+	return nil
+}
+
+type catchResult struct {
+	Pokemon  string `json:"pokemon"`
+	Location string `json:"location"`
+	// false when the pokemon didn't show up, no ball is thrown then
+	Appeared bool   `json:"appeared"`
+	Method   string `json:"method"`
+	Level    int    `json:"level"`
+	// the rest is only set when the pokemon appeared
+	Ball        string  `json:"ball"`
+	Probability float64 `json:"probability"`
+	Caught      bool    `json:"caught"`
+	// and these when it was caught
+	ID       int    `json:"id,omitempty"`
+	Nickname string `json:"nickname,omitempty"`
+	InParty  bool   `json:"in_party,omitempty"`
+}
+
+func (r catchResult) Text(w io.Writer) error {
+	if !r.Appeared {
+		_, err := fmt.Fprintf(w, "You searched %s by %s but no %s showed up\n", r.Location, r.Method, r.Pokemon)
+		return err
+	}
+	fmt.Fprintf(w, "A wild %s (lv%d) appeared by %s!\n", r.Pokemon, r.Level, r.Method)
+	fmt.Fprintf(w, "A %s had a %.1f%% chance of catching %s\n", r.Ball, r.Probability*100, r.Pokemon)
+	if r.Caught {
+		fmt.Fprintf(w, "%s was caught and added to your Pokedex\n", r.Pokemon)
+		if r.InParty {
+			_, err := fmt.Fprintf(w, "It joined your party as #%d\n", r.ID)
+			return err
+		}
+		_, err := fmt.Fprintf(w, "Your party is full, so it was sent to your box as #%d\n", r.ID)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s managed to not get caught\n", r.Pokemon)
+	return err
+}
+
+type gotoResult struct {
+	LocationArea string   `json:"location_area"`
+	Pokemon      []string `json:"pokemon"`
+}
+
+func (r gotoResult) Text(w io.Writer) error {
+	fmt.Fprintf(w, "You are now in %s\n", r.LocationArea)
+	for _, name := range r.Pokemon {
+		fmt.Fprintln(w, name)
+	}
+	return nil
+}
+
+type statResult struct {
+	Name     string `json:"name"`
+	BaseStat int    `json:"base_stat"`
+}
+
+type inspectResult struct {
+	Name   string `json:"name"`
+	Height int    `json:"height"`
+	Weight int    `json:"weight"`
+	Sprite string `json:"sprite,omitempty"`
+	// the sprite drawn for the terminal, by inspect --sprite
+	Art   string       `json:"-"`
+	Types []string     `json:"types"`
+	Stats []statResult `json:"stats"`
+}
+
+// types and sprite are the ones the pokemon had in game
+func newInspectResult(p pokemon.PokemonEndpoint, game pokemon.Game) inspectResult {
+	res := inspectResult{
+		Name:   p.Name,
+		Height: p.Height,
+		Weight: p.Weight,
+		Sprite: game.Sprite(p),
+		Types:  []string{},
+		Stats:  []statResult{},
+	}
+	res.Types = append(res.Types, game.Types(p)...)
+	for _, stat := range p.Stats {
+		res.Stats = append(res.Stats, statResult{Name: stat.Stat.Name, BaseStat: stat.BaseStat})
+	}
+	return res
+}
+
+func (r inspectResult) Text(w io.Writer) error {
+	fmt.Fprintf(w, "Pokemon: %s\n", r.Name)
+	fmt.Fprintf(w, "Height: %d | Weight: %d\n", r.Height, r.Weight)
+	if r.Sprite != "" {
+		fmt.Fprintf(w, "Sprite: %s\n", r.Sprite)
+	}
+	fmt.Fprint(w, r.Art)
+	fmt.Fprintln(w, "==TYPES==")
+	for _, pokemonType := range r.Types {
+		fmt.Fprintln(w, pokemonType)
+	}
+	fmt.Fprintln(w, "==STATS==")
+	for _, stat := range r.Stats {
+		fmt.Fprintf(w, "%s: %d\n", stat.Name, stat.BaseStat)
+	}
+	return nil
+}
+
+type pokedexResult struct {
+	Pokemon []string `json:"pokemon"`
+}
+
+func (r pokedexResult) Text(w io.Writer) error {
+	if len(r.Pokemon) == 0 {
+		_, err := fmt.Fprintln(w, "You haven't caught any Pokemons...YET!")
+		return err
+	}
+	fmt.Fprintln(w, "==Your Pokedex==")
+	for _, name := range r.Pokemon {
+		fmt.Fprintln(w, name)
+	}
+	return nil
+}
+
+type saveResult struct {
+	Path string `json:"path"`
+}
+
+func (r saveResult) Text(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Pokedex saved to %s\n", r.Path)
+	return err
+}
+
+type loadResult struct {
+	Path string `json:"path"`
+}
+
+func (r loadResult) Text(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Pokedex loaded from %s\n", r.Path)
+	return err
+}
+
+type snapshotResult struct {
+	Path          string `json:"path"`
+	LocationAreas int    `json:"location_areas"`
+}
+
+func (r snapshotResult) Text(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Snapshot of %d location areas saved to %s, start with --offline to use it\n", r.LocationAreas, r.Path)
+	return err
+}
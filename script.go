@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"github.com/srijan-raghavula/pokedex/internal/render"
+	"os"
+	"strings"
+)
+
+// exit codes for script and one-shot mode, the REPL keeps going whatever
+// a command does. the last three are from sysexits.h
+const (
+	exitOK          = 0
+	exitFailed      = 1
+	exitUsage       = 2
+	exitNoInput     = 66
+	exitUnavailable = 69
+	exitSoftware    = 70
+	// killed by SIGINT, like a shell reports it
+	exitInterrupted = 130
+)
+
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	switch kindOf(err) {
+	case kindUsage:
+		return exitUsage
+	case kindNetwork:
+		return exitUnavailable
+	case kindInternal:
+		return exitSoftware
+	case kindInterrupted:
+		return exitInterrupted
+	}
+	return exitFailed
+}
+
+// pulls --name, --name=value and --name value flags out of the words of a command
+func splitFlags(words []string) ([]string, map[string]string) {
+	args := make([]string, 0, len(words))
+	flags := make(map[string]string)
+	takesValue := valueFlags()
+	for i := 0; i < len(words); i++ {
+		name, ok := strings.CutPrefix(words[i], "--")
+		if !ok || name == "" {
+			args = append(args, words[i])
+			continue
+		}
+		name, value, hasValue := strings.Cut(name, "=")
+		name = strings.ToLower(name)
+		if !hasValue && takesValue[name] && i+1 < len(words) {
+			i++
+			value = words[i]
+		}
+		flags[name] = value
+	}
+	return args, flags
+}
+
+func hasFlag(flags map[string]string, name string) bool {
+	_, ok := flags[name]
+	return ok
+}
+
+// --json is kept as a shorthand for --output json
+func outputFormat(c *config, flags map[string]string) (render.Format, error) {
+	if hasFlag(flags, "json") {
+		return render.JSON, nil
+	}
+	if !hasFlag(flags, "output") {
+		return c.outputDefault, nil
+	}
+	output, err := render.ParseFormat(flags["output"])
+	if err != nil {
+		return "", usageError(err.Error())
+	}
+	return output, nil
+}
+
+// runs the command given on the command line, like `pokedex explore canalave-city-area --json`
+func runArgs(ctx context.Context, c *config, args []string) int {
+	err := runWords(ctx, c, args)
+	if err != nil {
+		printError(os.Stderr, c, err, "")
+	}
+	return finish(c, exitCode(err))
+}
+
+// runs every line of a script as a command and stops at the first one that fails.
+// blank lines and lines starting with # are skipped, "-" reads the script from stdin
+func runScript(ctx context.Context, c *config, path string) int {
+	f := os.Stdin
+	if path != "-" {
+		var err error
+		f, err = os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitNoInput
+		}
+		defer f.Close()
+	}
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		err := runCommand(ctx, c, line)
+		if err != nil {
+			printError(os.Stderr, c, err, fmt.Sprintf("%s:%d", path, lineNo))
+			return finish(c, exitCode(err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return finish(c, exitFailed)
+	}
+	return finish(c, exitOK)
+}
+
+// non-interactive runs save the Pokedex just like exit does
+func finish(c *config, code int) int {
+	err := pokemon.Pokemons.Save(c.savePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if code == exitOK {
+			return exitFailed
+		}
+	}
+	return code
+}
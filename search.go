@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"github.com/srijan-raghavula/pokedex/internal/search"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// the most names search prints
+const maxSearchResults = 20
+
+// searchIndex is the index of every name in PokeAPI, loaded from disk or
+// built again when there's none or it's older than a week
+func searchIndex(ctx context.Context, c *config) (*search.Index, error) {
+	if c.index != nil && !c.index.Stale(time.Now()) {
+		return c.index, nil
+	}
+	index, err := search.Load(c.indexPath)
+	if err == nil && !index.Stale(time.Now()) {
+		c.index = index
+		return index, nil
+	}
+	// a snapshot has no list of every name to build the index from
+	if c.offline {
+		if err != nil {
+			return nil, err
+		}
+		c.index = index
+		return index, nil
+	}
+	built, buildErr := search.Build(ctx, c.client)
+	if buildErr != nil {
+		// an old index beats none, like when offline
+		if err == nil {
+			c.index = index
+			return index, nil
+		}
+		return nil, buildErr
+	}
+	// the index is only a cache, it's built again next time if saving fails
+	built.Save(c.indexPath)
+	c.index = built
+	return built, nil
+}
+
+// suggest is the names of a kind closest to a misspelled one, none when
+// there's no index to look in
+func suggest(ctx context.Context, c *config, kind, name string) []string {
+	index, err := searchIndex(ctx, c)
+	if err != nil {
+		return nil
+	}
+	return index.Suggest(kind, name)
+}
+
+// withSuggestions adds the pokemon a misspelled name was probably meant to
+// be to the error of whichever command looked it up
+func withSuggestions(ctx context.Context, c *config, err error) error {
+	var unknown *pokemon.UnknownPokemonError
+	if errors.As(err, &unknown) && len(unknown.Suggestions) == 0 {
+		unknown.Suggestions = suggest(ctx, c, search.Pokemon, unknown.Name)
+	}
+	return err
+}
+
+func unknownAreaError(ctx context.Context, c *config, name string) error {
+	suggestions := suggest(ctx, c, search.LocationArea, name)
+	if len(suggestions) == 0 {
+		return errors.New("invalid location-area-name (possible spelling mistakes)")
+	}
+	return fmt.Errorf("invalid location-area-name %s, did you mean %s?", name, strings.Join(suggestions, " or "))
+}
+
+func searchNames(ctx context.Context, c *config, args ...string) (any, error) {
+	if len(args) < 2 {
+		return nil, errors.New("check the string passed into the function")
+	}
+	term, kind := args[0], args[1]
+	var kinds []string
+	if kind != "" {
+		if !slices.Contains(search.Kinds, kind) {
+			return nil, fmt.Errorf("unknown kind %q (use %s)", kind, strings.Join(search.Kinds, ", "))
+		}
+		kinds = []string{kind}
+	}
+	index, err := searchIndex(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	res := searchResult{Term: term, Matches: []search.Match{}}
+	res.Matches = append(res.Matches, index.Search(term, kinds, maxSearchResults)...)
+	return res, nil
+}
+
+type searchResult struct {
+	Term    string         `json:"term"`
+	Matches []search.Match `json:"matches"`
+}
+
+func (r searchResult) Text(w io.Writer) error {
+	if len(r.Matches) == 0 {
+		_, err := fmt.Fprintf(w, "Nothing matches %s\n", r.Term)
+		return err
+	}
+	for _, match := range r.Matches {
+		fmt.Fprintf(w, "%-13s %s\n", match.Kind, match.Name)
+	}
+	return nil
+}
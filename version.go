@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// settings outlive a session but aren't part of the Pokedex, so they have
+// a file of their own next to the save
+type settings struct {
+	Game pokemon.Game `json:"game"`
+}
+
+func settingsPath(savePath string) string {
+	return filepath.Join(filepath.Dir(savePath), "settings.json")
+}
+
+func loadSettings(c *config) {
+	body, err := os.ReadFile(settingsPath(c.savePath))
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	var s settings
+	if err == nil {
+		err = json.Unmarshal(body, &s)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not load your settings: %v\n", err)
+		return
+	}
+	c.game = s.Game
+}
+
+func saveSettings(c *config) error {
+	body, err := json.MarshalIndent(settings{Game: c.game}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := settingsPath(c.savePath)
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(body, '\n'), 0o644)
+}
+
+// version shows the game lookups are narrowed to, or picks another one
+func version(ctx context.Context, c *config, args ...string) (any, error) {
+	if len(args) == 0 {
+		return newVersionResult(c.game), nil
+	}
+	game := pokemon.Game{}
+	if args[0] != "all" {
+		var err error
+		game, err = pokemon.NewGame(ctx, c.client, args[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.game = game
+	c.encounter = nil
+	err := saveSettings(c)
+	if err != nil {
+		return nil, err
+	}
+	return newVersionResult(c.game), nil
+}
+
+type versionResult struct {
+	// empty for every version
+	Version      string `json:"version"`
+	VersionGroup string `json:"version_group"`
+	Generation   int    `json:"generation"`
+}
+
+func newVersionResult(game pokemon.Game) versionResult {
+	return versionResult{Version: game.Version, VersionGroup: game.VersionGroup, Generation: game.Generation}
+}
+
+func (r versionResult) Text(w io.Writer) error {
+	if r.Version == "" {
+		_, err := fmt.Fprintln(w, "Playing every version, use version <version-name> to pick one")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "Playing %s (%s, generation %d)\n", r.Version, r.VersionGroup, r.Generation)
+	return err
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/srijan-raghavula/pokedex/internal/encounter"
+	"github.com/srijan-raghavula/pokedex/internal/pokemon"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// looks for a wild pokemon in the current area. whatever shows up
+// can be caught with catch until the next walk or goto
+func walk(ctx context.Context, c *config, args ...string) (any, error) {
+	method, version, seedFlag := "", "", ""
+	if len(args) > 2 {
+		method, version, seedFlag = args[0], args[1], args[2]
+	}
+	seed := c.rng.Int63()
+	if seedFlag != "" {
+		n, err := strconv.ParseInt(seedFlag, 10, 64)
+		if err != nil {
+			return nil, usageOf("walk")
+		}
+		seed = n
+	}
+	if c.location == "" {
+		return nil, errors.New("You need to be somewhere to look for Pokemons, use goto <location-area-name> first")
+	}
+
+	area, err := c.client.LocationArea(ctx, c.location)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		version = c.game.Version
+	} else {
+		// a typo would otherwise look like a version with nothing in this area
+		_, err := pokemon.NewGame(ctx, c.client, version)
+		if err != nil {
+			return nil, err
+		}
+	}
+	filter := encounter.Filter{Version: version, Method: method}
+	wild, err := encounter.Sample(area, filter, rand.New(rand.NewSource(seed)))
+	if errors.Is(err, encounter.ErrNoEncounters) {
+		if method == "" {
+			method = encounter.DefaultMethod
+		}
+		methods := encounter.Methods(area, version)
+		if len(methods) == 0 {
+			return nil, fmt.Errorf("No Pokemons can be found in %s", area.Name)
+		}
+		return nil, fmt.Errorf("No Pokemons can be found by %s in %s (try %s)", method, area.Name, strings.Join(methods, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.encounter = &wild
+	return walkResult{LocationArea: area.Name, Seed: seed, Wild: wild}, nil
+}
+
+type walkResult struct {
+	LocationArea string `json:"location_area"`
+	// walking again with the same seed finds the same pokemon
+	Seed int64          `json:"seed"`
+	Wild encounter.Wild `json:"wild"`
+}
+
+func (r walkResult) Text(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "A wild %s (lv%d) appeared by %s in %s! (seed %d)\n", r.Wild.Pokemon, r.Wild.Level, r.Wild.Method, r.LocationArea, r.Seed)
+	return err
+}